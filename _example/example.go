@@ -20,15 +20,15 @@ func main() {
 
 	results, err := envconfig.Unmarshal(&s)
 	results.PrettyPrint() // safe if results are nil
-	// Env Variable         Type              OK
-	// ----                 ----              ----
-	// MYAPP_COLOR_CODES    map[string]int    v
-	// MYAPP_DEBUG          bool              v
-	// MYAPP_PORT           int               v
-	// MYAPP_RATE           float32           v
-	// MYAPP_TIMEOUT        time.Duration     v
-	// MYAPP_USER           string            v
-	// MYAPP_USERS          []string          v
+	// Env Variable         Type              Source    OK
+	// ----                 ----              ----      ----
+	// MYAPP_COLOR_CODES    map[string]int    env       v
+	// MYAPP_DEBUG          bool              env       v
+	// MYAPP_PORT           int               env       v
+	// MYAPP_RATE           float32           env       v
+	// MYAPP_TIMEOUT        time.Duration     env       v
+	// MYAPP_USER           string            env       v
+	// MYAPP_USERS          []string          env       v
 
 	if err != nil {
 		panic(err)
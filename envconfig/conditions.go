@@ -0,0 +1,158 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// condition is a KEY=value pair of the `required_if` tag.
+type condition struct {
+	Key   string
+	Value string
+}
+
+// conditions describe dependencies of the variable on other variables.
+//
+// The variable is required if values of all variables listed in the `required_if` tag
+// are equal to the specified ones or if any of the variables listed in the `required_with` tag
+// is set. Otherwise it is optional. The variable must not be set together with any of
+// the variables listed in the `excluded_with` tag.
+type conditions struct {
+	RequiredIf   []condition
+	RequiredWith []string
+	ExcludedWith []string
+}
+
+// parseConditions parses conditional tags. The prefix is prepended to keys
+// of variables the same way it is prepended to the key from the `env` tag.
+func parseConditions(tag reflect.StructTag, prefix string) (conditions, error) {
+	var c conditions
+	for _, pair := range splitTagList(tag.Get("required_if")) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return c, fmt.Errorf("required_if tag must be a list of KEY=value pairs, got %q", pair)
+		}
+		c.RequiredIf = append(c.RequiredIf, condition{Key: prefix + kv[0], Value: kv[1]})
+	}
+	for _, key := range splitTagList(tag.Get("required_with")) {
+		c.RequiredWith = append(c.RequiredWith, prefix+key)
+	}
+	for _, key := range splitTagList(tag.Get("excluded_with")) {
+		c.ExcludedWith = append(c.ExcludedWith, prefix+key)
+	}
+	return c, nil
+}
+
+// splitTagList splits a comma-separated value of the tag dropping empty items.
+func splitTagList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsConditional reports whether the variable is required only under some conditions.
+func (c conditions) IsConditional() bool {
+	return len(c.RequiredIf) > 0 || len(c.RequiredWith) > 0
+}
+
+func (c conditions) requiredIf() string {
+	pairs := make([]string, len(c.RequiredIf))
+	for i, cond := range c.RequiredIf {
+		pairs[i] = cond.Key + "=" + cond.Value
+	}
+	return "required if " + strings.Join(pairs, " and ")
+}
+
+func (c conditions) requiredWith() string {
+	return "required with " + strings.Join(c.RequiredWith, " or ")
+}
+
+// String describes the conditions in a human readable form.
+func (c conditions) String() string {
+	var parts []string
+	if len(c.RequiredIf) > 0 {
+		parts = append(parts, c.requiredIf())
+	}
+	if len(c.RequiredWith) > 0 {
+		parts = append(parts, c.requiredWith())
+	}
+	if len(c.ExcludedWith) > 0 {
+		parts = append(parts, "excluded with "+strings.Join(c.ExcludedWith, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Check evaluates the conditions of the variable with the specified key
+// given its lookup result and values of other variables.
+func (c conditions) Check(key string, lookup lookupResult, values resolvedValues) error {
+	if lookup.NotSet {
+		if len(c.RequiredIf) > 0 {
+			matched := true
+			for _, cond := range c.RequiredIf {
+				matched = matched && values.Equal(cond.Key, cond.Value)
+			}
+			if matched {
+				return &MissingVariableError{Key: key, Condition: c.requiredIf()}
+			}
+		}
+		for _, other := range c.RequiredWith {
+			if values.Get(other).IsSet() {
+				return &MissingVariableError{Key: key, Condition: c.requiredWith()}
+			}
+		}
+		return nil
+	}
+
+	if !lookup.IsSet() {
+		return nil
+	}
+	for _, other := range c.ExcludedWith {
+		if values.Get(other).IsSet() {
+			return &ExcludedVariableError{Key: key, With: other}
+		}
+	}
+	return nil
+}
+
+// resolvedValues gives access to values of all variables after lookup.
+type resolvedValues struct {
+	lookups map[string]lookupResult
+	types   map[string]reflect.Type
+	l       Lookuper
+}
+
+// Get returns the lookup result of the variable of the specification or,
+// if the specification has no such variable, looks it up directly.
+func (r resolvedValues) Get(key string) lookupResult {
+	if lookup, ok := r.lookups[key]; ok {
+		return lookup
+	}
+	value, source, ok := lookupSource(r.l, key)
+	if !ok {
+		return lookupResult{NotSet: true}
+	}
+	return lookupResult{Value: value, Source: source}
+}
+
+// Equal reports whether the variable has a value equal to the specified one.
+// Values of variables of the specification are compared after parsing both into the type
+// of the field, e.g. "1" equals "true" for a bool field. Other values are compared as strings.
+func (r resolvedValues) Equal(key, value string) bool {
+	lookup := r.Get(key)
+	if lookup.Source == "" {
+		return false
+	}
+	if typ, ok := r.types[key]; ok {
+		a, errA := parseParam(lookup.Value, typ)
+		b, errB := parseParam(value, typ)
+		if errA == nil && errB == nil {
+			return reflect.DeepEqual(a.Interface(), b.Interface())
+		}
+	}
+	return lookup.Value == value
+}
@@ -0,0 +1,111 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type conditionalSpec struct {
+	TLSEnabled bool   `env:"TLS_ENABLED" default:"false"`
+	TLSCert    string `env:"TLS_CERT" required_if:"TLS_ENABLED=true"`
+	User       string `env:"USER" optional:"true"`
+	Password   string `env:"PASSWORD" required_with:"USER" excluded_with:"TOKEN"`
+	Token      string `env:"TOKEN" optional:"true"`
+}
+
+func TestConditions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		vars MapLookuper
+		errs []error
+	}{
+		{
+			name: "nothing set",
+			vars: MapLookuper{},
+		},
+		{
+			name: "toggle on",
+			vars: MapLookuper{"ENV_CONFIG_TLS_ENABLED": "true"},
+			errs: []error{&MissingVariableError{Key: "ENV_CONFIG_TLS_CERT", Condition: "required if ENV_CONFIG_TLS_ENABLED=true"}},
+		},
+		{
+			name: "toggle on as a number",
+			vars: MapLookuper{"ENV_CONFIG_TLS_ENABLED": "1"},
+			errs: []error{&MissingVariableError{Key: "ENV_CONFIG_TLS_CERT", Condition: "required if ENV_CONFIG_TLS_ENABLED=true"}},
+		},
+		{
+			name: "toggle on in upper case",
+			vars: MapLookuper{"ENV_CONFIG_TLS_ENABLED": "TRUE"},
+			errs: []error{&MissingVariableError{Key: "ENV_CONFIG_TLS_CERT", Condition: "required if ENV_CONFIG_TLS_ENABLED=true"}},
+		},
+		{
+			name: "toggle off as a number",
+			vars: MapLookuper{"ENV_CONFIG_TLS_ENABLED": "0"},
+		},
+		{
+			name: "toggle on and set",
+			vars: MapLookuper{"ENV_CONFIG_TLS_ENABLED": "true", "ENV_CONFIG_TLS_CERT": "cert.pem"},
+		},
+		{
+			name: "required with",
+			vars: MapLookuper{"ENV_CONFIG_USER": "admin"},
+			errs: []error{&MissingVariableError{Key: "ENV_CONFIG_PASSWORD", Condition: "required with ENV_CONFIG_USER"}},
+		},
+		{
+			name: "excluded with",
+			vars: MapLookuper{"ENV_CONFIG_PASSWORD": "s3cr3t", "ENV_CONFIG_TOKEN": "t0k3n"},
+			errs: []error{&ExcludedVariableError{Key: "ENV_CONFIG_PASSWORD", With: "ENV_CONFIG_TOKEN"}},
+		},
+	}
+
+	for _, test := range tests {
+		var s conditionalSpec
+		results, _ := UnmarshalWithOptions(&s, Options{Lookuper: test.vars, Prefix: "ENV_CONFIG_"})
+		assert.Equal(t, test.errs, results.Errors(), test.name)
+	}
+}
+
+func TestConditionsError(t *testing.T) {
+	t.Parallel()
+
+	var s conditionalSpec
+	_, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{"ENV_CONFIG_TLS_ENABLED": "true"},
+		Prefix:   "ENV_CONFIG_",
+	})
+	assert.EqualError(t, err, `env variable is not set: "ENV_CONFIG_TLS_CERT" (required if ENV_CONFIG_TLS_ENABLED=true)`)
+
+	var missingErr *MissingVariableError
+	assert.True(t, errors.As(err, &missingErr))
+}
+
+func TestConditionsInvalidSpecification(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Cert string `env:"TLS_CERT" required_if:"TLS_ENABLED"`
+	}
+	_, err := UnmarshalFrom(&s, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+}
+
+func TestConditionsUsage(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Cert     string `env:"TLS_CERT" required_if:"TLS_ENABLED=true" comment:"path to the certificate"`
+		Password string `env:"PASSWORD" required_with:"USER,LOGIN" excluded_with:"TOKEN"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := Usagef(&s, buf, "{{range .}}{{usage_key .}}|{{usage_description .}}\n{{end}}")
+	require.NoError(t, err)
+	assert.Equal(t, "PASSWORD|(required with USER or LOGIN; excluded with TOKEN)\n"+
+		"TLS_CERT|path to the certificate (required if TLS_ENABLED=true)\n", buf.String())
+}
@@ -0,0 +1,303 @@
+package envconfig
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// DotenvError indicates that a dotenv file is malformed.
+type DotenvError struct {
+	Filename string
+	Line     int
+	Msg      string
+}
+
+func (e *DotenvError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Filename, e.Line, e.Msg)
+}
+
+// LoadDotenv reads variables from the dotenv file. The returned lookuper
+// reports the filename as a source of its values.
+func LoadDotenv(filename string) (Lookuper, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars, err := ParseDotenv(f, filename)
+	if err != nil {
+		return nil, err
+	}
+	return NamedLookuper(filename, vars), nil
+}
+
+// ParseDotenv parses variables in the dotenv format. The name is used in error messages.
+//
+// Each line contains a KEY=VALUE pair optionally prefixed with `export`.
+// Lines starting with # are comments. Unquoted values end at the end of the line
+// or at a # preceded by whitespace. Single-quoted values are taken literally.
+// Double-quoted values may span multiple lines and support \n, \r, \t, \", \\ and \$ escapes.
+// ${VAR} references in unquoted and double-quoted values are replaced with variables
+// defined earlier in the file or, if there are none, with variables of the process environment.
+func ParseDotenv(r io.Reader, name string) (MapLookuper, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := dotenvParser{
+		name: name,
+		src:  string(data),
+		line: 1,
+		vars: make(MapLookuper),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.vars, nil
+}
+
+type dotenvParser struct {
+	name string
+	src  string
+	pos  int
+	line int
+	vars MapLookuper
+}
+
+func (p *dotenvParser) errorf(line int, format string, args ...interface{}) error {
+	return &DotenvError{Filename: p.name, Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *dotenvParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.next()
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+func (p *dotenvParser) parse() error {
+	for {
+		for !p.eof() && strings.IndexByte(" \t\r\n", p.peek()) >= 0 {
+			p.next()
+		}
+		if p.eof() {
+			return nil
+		}
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+		if err := p.parseAssignment(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dotenvParser) parseAssignment() error {
+	line := p.line
+
+	key := p.parseKey()
+	if key == "export" && !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipBlanks()
+		key = p.parseKey()
+	}
+	if key == "" {
+		return p.errorf(line, "invalid variable name")
+	}
+
+	p.skipBlanks()
+	if p.eof() || p.peek() != '=' {
+		return p.errorf(line, "expected = after variable name %s", key)
+	}
+	p.next()
+	p.skipBlanks()
+
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case p.eof():
+	case p.peek() == '\'':
+		value, err = p.parseSingleQuoted()
+	case p.peek() == '"':
+		value, err = p.parseDoubleQuoted()
+	default:
+		value, err = p.parseUnquoted()
+	}
+	if err != nil {
+		return err
+	}
+
+	p.vars[key] = value
+	return nil
+}
+
+func (p *dotenvParser) parseKey() string {
+	start := p.pos
+	for !p.eof() && isDotenvKeyChar(p.peek(), p.pos == start) {
+		p.next()
+	}
+	return p.src[start:p.pos]
+}
+
+func isDotenvKeyChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		return true
+	case c == '.', c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+func (p *dotenvParser) parseSingleQuoted() (string, error) {
+	line := p.line
+	p.next()
+
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' && p.peek() != '\n' {
+		p.next()
+	}
+	if p.eof() || p.peek() != '\'' {
+		return "", p.errorf(line, "unterminated single-quoted value")
+	}
+	value := p.src[start:p.pos]
+	p.next()
+
+	return value, p.parseLineEnd()
+}
+
+func (p *dotenvParser) parseDoubleQuoted() (string, error) {
+	line := p.line
+	p.next()
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf(line, "unterminated double-quoted value")
+		}
+		c := p.next()
+		switch c {
+		case '"':
+			return b.String(), p.parseLineEnd()
+		case '\\':
+			if p.eof() {
+				return "", p.errorf(line, "unterminated double-quoted value")
+			}
+			switch e := p.next(); e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(e)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case '$':
+			if err := p.parseReference(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *dotenvParser) parseUnquoted() (string, error) {
+	var b strings.Builder
+	for !p.eof() && p.peek() != '\n' {
+		if p.peek() == '#' && b.Len() > 0 {
+			if last := b.String()[b.Len()-1]; last == ' ' || last == '\t' {
+				p.skipLine()
+				break
+			}
+		}
+		c := p.next()
+		if c == '$' {
+			if err := p.parseReference(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// parseReference expands a ${VAR} reference. The leading $ is already consumed.
+func (p *dotenvParser) parseReference(b *strings.Builder) error {
+	if p.eof() || p.peek() != '{' {
+		b.WriteByte('$')
+		return nil
+	}
+
+	line := p.line
+	p.next()
+	start := p.pos
+	for !p.eof() && p.peek() != '}' && p.peek() != '\n' {
+		p.next()
+	}
+	if p.eof() || p.peek() != '}' {
+		return p.errorf(line, "unterminated variable reference")
+	}
+	name := p.src[start:p.pos]
+	p.next()
+
+	if name == "" {
+		return p.errorf(line, "empty variable reference")
+	}
+	value, ok := p.vars[name]
+	if !ok {
+		value, _ = os.LookupEnv(name)
+	}
+	b.WriteString(value)
+	return nil
+}
+
+// parseLineEnd makes sure that nothing but a comment follows a quoted value.
+func (p *dotenvParser) parseLineEnd() error {
+	line := p.line
+	p.skipBlanks()
+	if p.eof() {
+		return nil
+	}
+	switch p.peek() {
+	case '\r', '\n':
+		return nil
+	case '#':
+		p.skipLine()
+		return nil
+	}
+	return p.errorf(line, "unexpected character %q after quoted value", p.peek())
+}
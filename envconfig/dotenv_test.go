@@ -0,0 +1,94 @@
+package envconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Parallel()
+
+	const data = `
+# full line comment
+PLAIN=value
+export EXPORTED=exported
+  SPACES  =   spaced value   
+INLINE=value # comment
+HASH=value#not-a-comment
+EMPTY=
+SINGLE='single $PLAIN ${PLAIN} \n' # comment
+DOUBLE="double \"quoted\"\t\\ \$PLAIN"
+MULTI="first
+second"
+REF=${PLAIN}/${EXPORTED}
+QUOTED_REF="${MULTI}!"
+DOLLAR=$PLAIN
+`
+
+	vars, err := ParseDotenv(strings.NewReader(data), ".env")
+	require.NoError(t, err)
+
+	assert.Equal(t, MapLookuper{
+		"PLAIN":      "value",
+		"EXPORTED":   "exported",
+		"SPACES":     "spaced value",
+		"INLINE":     "value",
+		"HASH":       "value#not-a-comment",
+		"EMPTY":      "",
+		"SINGLE":     `single $PLAIN ${PLAIN} \n`,
+		"DOUBLE":     "double \"quoted\"\t\\ $PLAIN",
+		"MULTI":      "first\nsecond",
+		"REF":        "value/exported",
+		"QUOTED_REF": "first\nsecond!",
+		"DOLLAR":     "$PLAIN",
+	}, vars)
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{data: "A=1\n=2", err: ".env:2: invalid variable name"},
+		{data: "A=1\nB 2", err: ".env:2: expected = after variable name B"},
+		{data: "A='1\n'", err: ".env:1: unterminated single-quoted value"},
+		{data: "A=1\n\nB=\"2\n3", err: ".env:3: unterminated double-quoted value"},
+		{data: "A=\"1\" 2", err: ".env:1: unexpected character '2' after quoted value"},
+		{data: "A=${B", err: ".env:1: unterminated variable reference"},
+		{data: "A=${}", err: ".env:1: empty variable reference"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseDotenv(strings.NewReader(test.data), ".env")
+		var dotenvErr *DotenvError
+		require.True(t, errors.As(err, &dotenvErr), test.data)
+		assert.Equal(t, test.err, err.Error())
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Port int    `env:"ENV_CONFIG_PORT"`
+		User string `env:"ENV_CONFIG_USER"`
+	}
+
+	l, err := LoadDotenv("testdata/test.env")
+	require.NoError(t, err)
+
+	results, err := UnmarshalFrom(&s, l)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, s.Port)
+	assert.Equal(t, "Kelsey", s.User)
+	for _, result := range results {
+		assert.Equal(t, "testdata/test.env", result.Source)
+	}
+}
@@ -0,0 +1,194 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MultiError is returned by Unmarshal when one or more fields cannot be unmarshaled.
+// It holds errors of all failed fields. errors.Is and errors.As match any of them.
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the underlying errors.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the underlying errors matches target.
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first underlying error that matches target, and if so,
+// sets target to that error value and returns true.
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingVariableError indicates that a required variable is not set.
+// Condition describes why a conditional variable is required.
+type MissingVariableError struct {
+	Key       string
+	Condition string
+}
+
+func (e *MissingVariableError) Error() string {
+	if e.Condition != "" {
+		return fmt.Sprintf("env variable is not set: %q (%s)", e.Key, e.Condition)
+	}
+	return fmt.Sprintf("env variable is not set: %q", e.Key)
+}
+
+// ExcludedVariableError indicates that a variable is set together with
+// a variable listed in its `excluded_with` tag.
+type ExcludedVariableError struct {
+	Key  string
+	With string
+}
+
+func (e *ExcludedVariableError) Error() string {
+	return fmt.Sprintf("env variable %q must not be set together with %q", e.Key, e.With)
+}
+
+// EmptyValueError indicates that a variable with the `notempty` tag
+// is set to an empty or whitespace-only value.
+type EmptyValueError struct {
+	Key string
+}
+
+func (e *EmptyValueError) Error() string {
+	return fmt.Sprintf("env variable is empty: %q", e.Key)
+}
+
+// ParseError indicates that a value of the variable cannot be converted
+// to the type of the struct field. Value of the sensitive field is redacted.
+type ParseError struct {
+	Key   string
+	Field string
+	Type  string
+	Value string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("assigning %s=%q to %s type %s: %v", e.Key, e.Value, e.Field, e.Type, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError indicates that the value of the variable violates a validation tag.
+// Rule is the name of the tag and Param is its value.
+type ValidationError struct {
+	Key   string
+	Rule  string
+	Param string
+	Msg   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("env variable %q is invalid: %s", e.Key, e.Msg)
+}
+
+// DuplicateKeyError indicates that several struct fields use the same variable.
+type DuplicateKeyError struct {
+	Key    string
+	Fields []string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate env variable name %s for %s", e.Key, strings.Join(e.Fields, ", "))
+}
+
+// UnknownVariableError indicates that a variable with the prefix is set
+// but not used by any of the struct fields. Suggestion is the closest known key if any.
+type UnknownVariableError struct {
+	Key        string
+	Suggestion string
+}
+
+func (e *UnknownVariableError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown env variable is set: %q; did you mean %q?", e.Key, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown env variable is set: %q", e.Key)
+}
+
+// GroupError indicates that the number of variables of the group which are set
+// violates the rule of the group. Keys are all members of the group and Set are
+// members which are set.
+type GroupError struct {
+	Group string
+	Rule  string
+	Keys  []string
+	Set   []string
+}
+
+func (e *GroupError) Error() string {
+	var rule string
+	switch e.Rule {
+	case GroupExactlyOne:
+		rule = "exactly one"
+	case GroupAtMostOne:
+		rule = "at most one"
+	case GroupAtLeastOne:
+		rule = "at least one"
+	}
+	got := "none"
+	if len(e.Set) > 0 {
+		got = quoteKeys(e.Set)
+	}
+	return fmt.Sprintf("group %s: %s of %s must be set, got %s", e.Group, rule, quoteKeys(e.Keys), got)
+}
+
+func quoteKeys(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = strconv.Quote(key)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// CycleError indicates that the variable cannot be expanded because
+// it references itself directly or through other variables.
+type CycleError struct {
+	Key   string
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("cyclic reference in env variable %q: %s", e.Key, strings.Join(e.Cycle, " -> "))
+}
+
+func (e *CycleError) contains(key string) bool {
+	for _, k := range e.Cycle {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
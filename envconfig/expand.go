@@ -0,0 +1,200 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IsExpand reports whether references to other variables in the value of the variable
+// are expanded. The `expand` tag takes precedence over the global option.
+func (info *envVarInfo) IsExpand(global bool) bool {
+	value, ok := info.Tag.Lookup("expand")
+	if !ok {
+		return global
+	}
+	expand, _ := strconv.ParseBool(value)
+	return expand
+}
+
+// expander replaces ${VAR} and ${VAR:-fallback} references in values of the variables.
+// A reference to a variable of the specification is replaced with its resolved value,
+// which is expanded first if needed. Other variables are looked up directly.
+// A variable referencing a sensitive variable becomes sensitive too.
+type expander struct {
+	infos   []envVarInfo
+	lookups []lookupResult
+	errs    []error
+	l       Lookuper
+	global  bool
+
+	index  map[string]int
+	done   []bool
+	active []bool
+	path   []string
+}
+
+func newExpander(infos []envVarInfo, lookups []lookupResult, errs []error, l Lookuper, global bool) *expander {
+	index := make(map[string]int, len(infos))
+	for i, info := range infos {
+		index[info.Key] = i
+	}
+	return &expander{
+		infos:   infos,
+		lookups: lookups,
+		errs:    errs,
+		l:       l,
+		global:  global,
+		index:   index,
+		done:    make([]bool, len(infos)),
+		active:  make([]bool, len(infos)),
+	}
+}
+
+// ExpandAll expands values of all variables in place. The raw value is kept in RawValue.
+// Errors are stored along with errors of the lookup.
+func (e *expander) ExpandAll() {
+	for i := range e.infos {
+		_ = e.expandAt(i)
+	}
+}
+
+// expandAt expands the value of the i-th variable unless it is already expanded.
+// A *CycleError is returned if the variable is being expanded already.
+func (e *expander) expandAt(i int) error {
+	if e.done[i] {
+		return nil
+	}
+	info := &e.infos[i]
+	if e.active[i] {
+		start := 0
+		for e.path[start] != info.Key {
+			start++
+		}
+		cycle := append(append([]string(nil), e.path[start:]...), info.Key)
+		return &CycleError{Cycle: cycle}
+	}
+	if e.errs[i] != nil || e.lookups[i].NotSet || !info.IsExpand(e.global) {
+		e.done[i] = true
+		return nil
+	}
+
+	e.active[i] = true
+	e.path = append(e.path, info.Key)
+	value, err := e.expand(e.lookups[i].Value)
+	e.path = e.path[:len(e.path)-1]
+	e.active[i] = false
+	e.done[i] = true
+
+	var cycleErr *CycleError
+	switch {
+	case errors.As(err, &cycleErr):
+		e.errs[i] = &CycleError{Key: info.Key, Cycle: cycleErr.Cycle}
+		return err
+	case err != nil:
+		e.errs[i] = fmt.Errorf("expand %s: %w", info.Key, err)
+		return nil
+	}
+	e.lookups[i].RawValue = e.lookups[i].Value
+	e.lookups[i].Value = value
+	return nil
+}
+
+// resolve returns the value of the referenced variable.
+// Variables that failed or are not set are treated as unset.
+func (e *expander) resolve(key string) (string, bool, error) {
+	i, ok := e.index[key]
+	if !ok {
+		value, _, ok := lookupSource(e.l, key)
+		return value, ok, nil
+	}
+
+	if err := e.expandAt(i); err != nil {
+		// the error is propagated only to variables in the cycle
+		var cycleErr *CycleError
+		if errors.As(err, &cycleErr) && cycleErr.contains(e.path[len(e.path)-1]) {
+			return "", false, err
+		}
+	}
+	if e.errs[i] != nil || e.lookups[i].NotSet {
+		return "", false, nil
+	}
+	// the resolved value must not be revealed by the variable being expanded
+	if e.infos[i].IsSensitive() {
+		current := &e.infos[e.index[e.path[len(e.path)-1]]]
+		if !current.IsSensitive() {
+			current.Sensitive = "true"
+		}
+	}
+	return e.lookups[i].Value, true, nil
+}
+
+// expand replaces references in the value. $$ is replaced with a single $.
+func (e *expander) expand(value string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(value, '$')
+		if i < 0 || i == len(value)-1 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		b.WriteString(value[:i])
+		value = value[i+1:]
+
+		switch value[0] {
+		case '$':
+			b.WriteByte('$')
+			value = value[1:]
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			continue
+		}
+
+		end := matchingBrace(value)
+		if end < 0 {
+			return "", errors.New("unterminated variable reference")
+		}
+		ref := value[1:end]
+		value = value[end+1:]
+
+		name, fallback, hasFallback := ref, "", false
+		if j := strings.Index(ref, ":-"); j >= 0 {
+			name, fallback, hasFallback = ref[:j], ref[j+2:], true
+		}
+		if name == "" {
+			return "", errors.New("empty variable reference")
+		}
+
+		resolved, ok, err := e.resolve(name)
+		if err != nil {
+			return "", err
+		}
+		if hasFallback && (!ok || resolved == "") {
+			if resolved, err = e.expand(fallback); err != nil {
+				return "", err
+			}
+		}
+		b.WriteString(resolved)
+	}
+}
+
+// matchingBrace returns the index of the brace that closes the brace at the start of s
+// or -1 if there is none.
+func matchingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+package envconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Host     string `env:"MYAPP_HOST" default:"localhost"`
+		Port     int    `env:"MYAPP_PORT" default:"8080"`
+		URL      string `env:"MYAPP_URL" default:"http://${MYAPP_HOST}:${MYAPP_PORT}" expand:"true"`
+		CacheDir string `env:"MYAPP_CACHE_DIR" default:"${HOME}/.cache/myapp" expand:"true"`
+		LogDir   string `env:"MYAPP_LOG_DIR" expand:"true"`
+		Raw      string `env:"MYAPP_RAW"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"HOME":          "/home/user",
+		"MYAPP_HOST":    "example.com",
+		"MYAPP_LOG_DIR": "${LOG_ROOT:-/var/log}/${MYAPP_NAME:-myapp}$$",
+		"MYAPP_RAW":     "${HOME}",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://example.com:8080", s.URL)
+	assert.Equal(t, "/home/user/.cache/myapp", s.CacheDir)
+	assert.Equal(t, "/var/log/myapp$", s.LogDir)
+	assert.Equal(t, "${HOME}", s.Raw)
+
+	assert.Equal(t, "MYAPP_URL", results[5].KeyName)
+	assert.Equal(t, "http://example.com:8080", results[5].Value)
+	assert.Equal(t, "http://${MYAPP_HOST}:${MYAPP_PORT}", results[5].RawValue)
+	assert.Equal(t, "", results[4].RawValue)
+}
+
+func TestExpandGlobal(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Home  string `env:"HOME_DIR"`
+		Data  string `env:"DATA_DIR" default:"${HOME_DIR}/data"`
+		Regex string `env:"REGEX" expand:"false"`
+	}
+
+	_, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{"HOME_DIR": "/home/${USER}", "USER": "gopher", "REGEX": "^a${2}$"},
+		Expand:   true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/home/gopher", s.Home)
+	assert.Equal(t, "/home/gopher/data", s.Data)
+	assert.Equal(t, "^a${2}$", s.Regex)
+}
+
+func TestExpandCycle(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		A string `env:"A"`
+		B string `env:"B"`
+		C string `env:"C"`
+	}
+
+	results, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{"A": "${B}", "B": "x${A}", "C": "${A:-c}"},
+		Expand:   true,
+	})
+	assert.Equal(t, []error{
+		&CycleError{Key: "A", Cycle: []string{"A", "B", "A"}},
+		&CycleError{Key: "B", Cycle: []string{"A", "B", "A"}},
+	}, results.Errors())
+	assert.EqualError(t, err, `cyclic reference in env variable "A": A -> B -> A`+"\n"+
+		`cyclic reference in env variable "B": A -> B -> A`)
+
+	var cycleErr *CycleError
+	assert.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, "c", s.C)
+}
+
+func TestExpandSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		A string `env:"A" expand:"true"`
+		B string `env:"B" expand:"true"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{"A": "${B", "B": "${}"})
+	assert.EqualError(t, err, "expand A: unterminated variable reference\nexpand B: empty variable reference")
+}
+
+func TestExpandSensitive(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		DSN string `env:"DSN" default:"postgres://app:${PASSWORD}@db" expand:"true" sensitive:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{"PASSWORD": "s3cr3t"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://app:s3cr3t@db", s.DSN)
+	assert.Equal(t, "******", results[0].Value)
+	assert.Equal(t, "******", results[0].RawValue)
+}
+
+func TestExpandReferenceToSensitive(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Password string `env:"P" sensitive:"last4"`
+		DSN      string `env:"D" default:"x${P}" expand:"true"`
+		URL      string `env:"U" default:"${D}@db" expand:"true"`
+		Port     int    `env:"PORT" default:"${P}" expand:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{"P": "secret"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+
+	assert.Equal(t, "xsecret", s.DSN)
+	assert.Equal(t, "xsecret@db", s.URL)
+	for _, result := range results {
+		if result.KeyName == "P" {
+			continue
+		}
+		assert.True(t, result.Sensitive, result.KeyName)
+		assert.Equal(t, "******", result.Value, result.KeyName)
+	}
+}
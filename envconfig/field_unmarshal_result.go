@@ -9,21 +9,48 @@ var output io.Writer = os.Stdout
 
 // A FieldUnmarshalResult. Err is filled when an environment variable is required but missing or
 // cannot be converted to the type required by a struct field during assignment.
+// Source is the name of the lookuper that supplied the value or SourceDefault
+// when the value was taken from the `default` tag. File is the path to the file
+// the value was read from when the variable with the _FILE suffix was used.
+// Default is the value of the `default` tag. Warning is set when the value
+// was supplied by a deprecated alias of the variable. NotSet is true when
+// the variable with the `optional` tag is not set and the field is left intact.
+// RawValue is the value before expansion of references to other variables
+// and is empty when the value is not expanded.
+// Value, RawValue and Default of the field with the `sensitive` tag or of the field whose value
+// references a sensitive variable are redacted and Sensitive is set.
 type FieldUnmarshalResult struct {
 	KeyName   string
 	FieldName string
 	TypeName  string
 	Value     string
+	RawValue  string
+	Sensitive bool
+	Source    string
+	File      string
+	Default   string
+	Warning   string
+	NotSet    bool
 	Err       error
 }
 
 type FieldUnmarshalResults []FieldUnmarshalResult
 
-func (f FieldUnmarshalResults) firstError() error {
+// Errors returns errors of all fields that failed to unmarshal.
+func (f FieldUnmarshalResults) Errors() []error {
+	var errs []error
 	for _, result := range f {
 		if result.Err != nil {
-			return result.Err
+			errs = append(errs, result.Err)
 		}
 	}
-	return nil
+	return errs
+}
+
+func (f FieldUnmarshalResults) err() error {
+	errs := f.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return &MultiError{Errors: errs}
 }
@@ -3,7 +3,6 @@ package envconfig
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -18,26 +17,114 @@ func FindUnknownEnvVariablesByPrefix(prefix string, spec interface{}) ([]string,
 	if prefix == "" {
 		return nil, fmt.Errorf("%w", ErrEmptyPrefix)
 	}
-	infos, err := gatherInfo(spec)
+	l := OsLookuper{}
+	infos, err := gatherInfo(spec, "", l)
 	if err != nil {
 		return nil, fmt.Errorf("gather info: %w", err)
 	}
 
+	return findUnknownKeys(prefix, l.Keys(), infos), nil
+}
+
+// findUnknownKeys returns keys with the prefix that are not used by any of the variables.
+func findUnknownKeys(prefix string, keys []string, infos []envVarInfo) []string {
 	vars := make(map[string]struct{})
 	for _, info := range infos {
-		vars[info.Key] = struct{}{}
+		for _, key := range info.Keys() {
+			vars[key] = struct{}{}
+		}
 	}
 
 	var unknownVars []string
-	for _, env := range os.Environ() {
-		if !strings.HasPrefix(env, prefix) {
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
 			continue
 		}
-		v := strings.SplitN(env, "=", 2)[0]
-		if _, found := vars[v]; !found {
-			unknownVars = append(unknownVars, v)
+		if _, found := vars[key]; !found {
+			unknownVars = append(unknownVars, key)
 		}
 	}
+	return unknownVars
+}
+
+// unknownResults lists variables with the prefix that are set in the lookuper
+// but not used by any of the variables. Each of them is reported as a failed result.
+func unknownResults(prefix string, l Lookuper, infos []envVarInfo) (FieldUnmarshalResults, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("strict mode: %w", ErrEmptyPrefix)
+	}
+	keys, ok := listKeys(l)
+	if !ok {
+		return nil, fmt.Errorf("strict mode: lookuper %T must implement Lister", l)
+	}
 
-	return unknownVars, nil
+	var results FieldUnmarshalResults
+	for _, key := range findUnknownKeys(prefix, keys, infos) {
+		_, source, _ := lookupSource(l, key)
+		results = append(results, FieldUnmarshalResult{
+			KeyName: key,
+			Source:  source,
+			Err:     &UnknownVariableError{Key: key, Suggestion: suggestKey(prefix, key, infos)},
+		})
+	}
+	return results, nil
+}
+
+// suggestKey returns a key of the variable that is closest to the unknown key
+// by edit distance or empty string if none of them is close enough.
+func suggestKey(prefix, unknown string, infos []envVarInfo) string {
+	unknown = strings.TrimPrefix(unknown, prefix)
+	maxDistance := len(unknown) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	var suggestion string
+	for _, info := range infos {
+		if !strings.HasPrefix(info.Key, prefix) {
+			continue
+		}
+		distance := editDistance(unknown, strings.TrimPrefix(info.Key, prefix))
+		if distance <= maxDistance {
+			suggestion, maxDistance = info.Key, distance-1
+		}
+	}
+	return suggestion
+}
+
+// editDistance calculates the optimal string alignment distance between two strings,
+// that is a number of insertions, deletions, substitutions and transpositions
+// of adjacent characters required to turn one string into the other.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, minInt(d[i][j-1]+1, d[i-1][j-1]+cost))
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
 }
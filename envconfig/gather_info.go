@@ -2,9 +2,9 @@ package envconfig
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 	"sort"
+	"strconv"
 )
 
 // envVarInfo maintains information about the configuration variable
@@ -15,28 +15,67 @@ type envVarInfo struct {
 	Comment      string
 	Default      string
 	IsDefaultSet bool
+	IsFile       bool
+	Sensitive    string
+	Aliases      []string
+	IsOptional   bool
+	IsNotEmpty   bool
+	Tag          reflect.StructTag
+	Conditions   conditions
+	Group        string
+	GroupRule    string
+	// GroupKey tells apart groups with the same name in structs reused with different prefixes.
+	GroupKey string
+	// Store copies the unmarshaled value of Field to its destination which cannot be
+	// addressed directly or must not be touched while gathering information,
+	// e.g. an element of an indexed slice or an entry of a map.
+	Store func()
+	// Err is reported instead of the value of the variable which cannot be unmarshaled at all,
+	// e.g. an element of an indexed slice with an index out of range.
+	Err error
 }
 
-func (info *envVarInfo) GetValueFromEnv() (string, error) {
-	if info.Key == "" {
-		return "", fmt.Errorf(`"env" tag is empty on struct field: %s`, info.Name)
+// Description returns the comment of the variable followed by its conditions and group if any.
+func (info *envVarInfo) Description() string {
+	conditions := info.Conditions.String()
+	if info.Group != "" {
+		if conditions != "" {
+			conditions += "; "
+		}
+		conditions += "group " + info.Group
+	}
+	switch {
+	case conditions == "":
+		return info.Comment
+	case info.Comment == "":
+		return "(" + conditions + ")"
 	}
+	return fmt.Sprintf("%s (%s)", info.Comment, conditions)
+}
 
-	// `os.Getenv` cannot differentiate between an explicitly set empty value
-	// and an unset value. `os.LookupEnv` is preferred to `syscall.Getenv`.
-	value, ok := os.LookupEnv(info.Key)
-	if !ok {
-		if info.IsDefaultSet {
-			value = info.Default
-		} else {
-			return "", fmt.Errorf("env variable is not set: %q", info.Key)
-		}
+// IsSensitive reports whether the value of the variable must be redacted.
+func (info *envVarInfo) IsSensitive() bool {
+	return maskValue("", info.Sensitive) != ""
+}
+
+// MaskValue redacts the value if the variable is sensitive.
+func (info *envVarInfo) MaskValue(value string) string {
+	return maskValue(value, info.Sensitive)
+}
+
+// MaskedDefault returns the default value redacted if the variable is sensitive.
+func (info *envVarInfo) MaskedDefault() string {
+	if !info.IsDefaultSet {
+		return ""
 	}
-	return value, nil
+	return info.MaskValue(info.Default)
 }
 
-// gatherInfo gathers information about the specified struct
-func gatherInfo(spec interface{}) ([]envVarInfo, error) {
+// gatherInfo gathers information about the specified struct.
+// The prefix is prepended to keys of all variables. The lookuper is used to discover
+// elements of indexed slices and entries of maps populated from a prefix,
+// which are described with a placeholder index or name if it is nil.
+func gatherInfo(spec interface{}, prefix string, l Lookuper) ([]envVarInfo, error) {
 	s := reflect.ValueOf(spec)
 
 	if s.Kind() != reflect.Ptr {
@@ -62,17 +101,41 @@ func gatherInfo(spec interface{}) ([]envVarInfo, error) {
 
 		f = followPointerChain(f)
 
+		if isIndexed(ftype) {
+			elemInfos, err := gatherIndexed(f, ftype, prefix, l)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, elemInfos...)
+			continue
+		}
+		if _, ok := ftype.Tag.Lookup("envMapPrefix"); ok {
+			entryInfos, err := gatherMapPrefix(f, ftype, prefix, l)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, entryInfos...)
+			continue
+		}
+
 		// handle embedded and referenced structs
 		if f.Kind() == reflect.Struct && !implementsInterface(ftype.Type) {
 			embeddedPtr := f.Addr().Interface()
-			embeddedInfos, err := gatherInfo(embeddedPtr)
+			embeddedInfos, err := gatherInfo(embeddedPtr, prefix+ftype.Tag.Get("envPrefix"), l)
 			if err != nil {
 				return nil, err
 			}
 			infos = append(infos, embeddedInfos...)
 		} else {
+			if _, ok := ftype.Tag.Lookup("envPrefix"); ok {
+				return nil, fmt.Errorf("envPrefix tag is set on non-struct field %s: %w", ftype.Name, ErrInvalidSpecification)
+			}
 			// Capture information about the config variable
-			infos = append(infos, createEnvVarInfo(f, ftype))
+			info, err := createEnvVarInfo(f, ftype, prefix)
+			if err != nil {
+				return nil, fmt.Errorf("struct field %s: %v: %w", ftype.Name, err, ErrInvalidSpecification)
+			}
+			infos = append(infos, info)
 		}
 	}
 	sort.Slice(infos, func(i, j int) bool {
@@ -81,15 +144,60 @@ func gatherInfo(spec interface{}) ([]envVarInfo, error) {
 	return infos, nil
 }
 
-func createEnvVarInfo(f reflect.Value, ftype reflect.StructField) envVarInfo {
+func createEnvVarInfo(f reflect.Value, ftype reflect.StructField, prefix string) (envVarInfo, error) {
 	defaultValue, isDefaultSet := ftype.Tag.Lookup("default")
+	isFile, _ := strconv.ParseBool(ftype.Tag.Get("file"))
+	isOptional, _ := strconv.ParseBool(ftype.Tag.Get("optional"))
+	isNotEmpty, _ := strconv.ParseBool(ftype.Tag.Get("notempty"))
+	key := ftype.Tag.Get("env")
+	if key != "" {
+		key = prefix + key
+	}
+	var aliases []string
+	for _, alias := range splitTagList(ftype.Tag.Get("aliases")) {
+		aliases = append(aliases, prefix+alias)
+	}
+	if err := checkValidationTags(ftype.Tag, f.Type()); err != nil {
+		return envVarInfo{}, err
+	}
+	conditions, err := parseConditions(ftype.Tag, prefix)
+	if err != nil {
+		return envVarInfo{}, err
+	}
+	// groups of reused structs with different prefixes must not be merged
+	group := ftype.Tag.Get("group")
+	var groupKey string
+	if group != "" {
+		groupKey = prefix + group
+	}
 	return envVarInfo{
 		Name:         ftype.Name,
 		Field:        f,
 		Comment:      ftype.Tag.Get("comment"),
-		Key:          ftype.Tag.Get("env"),
+		Key:          key,
 		Default:      defaultValue,
 		IsDefaultSet: isDefaultSet,
+		IsFile:       isFile,
+		Sensitive:    ftype.Tag.Get("sensitive"),
+		Aliases:      aliases,
+		IsOptional:   isOptional,
+		IsNotEmpty:   isNotEmpty,
+		Tag:          ftype.Tag,
+		Conditions:   conditions,
+		Group:        group,
+		GroupRule:    ftype.Tag.Get("groupRule"),
+		GroupKey:     groupKey,
+	}, nil
+}
+
+// addStore makes Store of each of the infos call the store function after its own Store if any.
+func addStore(infos []envVarInfo, store func()) {
+	for i := range infos {
+		if nested := infos[i].Store; nested != nil {
+			infos[i].Store = func() { nested(); store() }
+		} else {
+			infos[i].Store = store
+		}
 	}
 }
 
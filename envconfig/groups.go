@@ -0,0 +1,102 @@
+package envconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rules of the `groupRule` tag.
+const (
+	// GroupExactlyOne requires exactly one variable of the group to be set.
+	GroupExactlyOne = "exactlyOne"
+	// GroupAtMostOne allows at most one variable of the group to be set.
+	GroupAtMostOne = "atMostOne"
+	// GroupAtLeastOne requires at least one variable of the group to be set.
+	GroupAtLeastOne = "atLeastOne"
+)
+
+// fieldGroup is a group of variables with the same `group` tag.
+type fieldGroup struct {
+	Name string
+	// ID is the name of the group prefixed the same way as keys of its variables.
+	ID   string
+	Rule string
+	Keys []string
+}
+
+// gatherGroups collects groups of the variables sorted by prefixed name.
+// The rule may be specified on any of the members, but all of them must agree.
+func gatherGroups(infos []envVarInfo) ([]fieldGroup, error) {
+	index := make(map[string]int)
+	var groups []fieldGroup
+	for _, info := range infos {
+		if info.Group == "" {
+			continue
+		}
+		i, ok := index[info.GroupKey]
+		if !ok {
+			i = len(groups)
+			index[info.GroupKey] = i
+			groups = append(groups, fieldGroup{Name: info.Group, ID: info.GroupKey})
+		}
+		g := &groups[i]
+		g.Keys = append(g.Keys, info.Key)
+
+		switch {
+		case info.GroupRule == "" || info.GroupRule == g.Rule:
+		case g.Rule != "":
+			return nil, fmt.Errorf("conflicting groupRule tags %q and %q in group %s: %w", g.Rule, info.GroupRule, g.Name, ErrInvalidSpecification)
+		default:
+			g.Rule = info.GroupRule
+		}
+	}
+
+	for _, g := range groups {
+		switch g.Rule {
+		case GroupExactlyOne, GroupAtMostOne, GroupAtLeastOne:
+		case "":
+			return nil, fmt.Errorf("groupRule tag is not set in group %s: %w", g.Name, ErrInvalidSpecification)
+		default:
+			return nil, fmt.Errorf("unknown groupRule tag %q in group %s: %w", g.Rule, g.Name, ErrInvalidSpecification)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].ID < groups[j].ID
+	})
+	return groups, nil
+}
+
+// Check verifies that the number of variables of the group which are set satisfies the rule.
+func (g fieldGroup) Check(values resolvedValues) error {
+	var set []string
+	for _, key := range g.Keys {
+		if values.Get(key).IsSet() {
+			set = append(set, key)
+		}
+	}
+
+	switch {
+	case g.Rule == GroupExactlyOne && len(set) != 1,
+		g.Rule == GroupAtMostOne && len(set) > 1,
+		g.Rule == GroupAtLeastOne && len(set) == 0:
+		return &GroupError{Group: g.Name, Rule: g.Rule, Keys: g.Keys, Set: set}
+	}
+	return nil
+}
+
+// groupResults checks rules of all groups. Each failure is reported
+// as a result with keys of all members of the group.
+func groupResults(groups []fieldGroup, values resolvedValues) FieldUnmarshalResults {
+	var results FieldUnmarshalResults
+	for _, g := range groups {
+		if err := g.Check(values); err != nil {
+			results = append(results, FieldUnmarshalResult{
+				KeyName: strings.Join(g.Keys, ","),
+				Err:     err,
+			})
+		}
+	}
+	return results
+}
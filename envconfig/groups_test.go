@@ -0,0 +1,135 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type groupSpec struct {
+	Password string `env:"PASSWORD" group:"auth" groupRule:"exactlyOne"`
+	Token    string `env:"TOKEN" group:"auth"`
+	CertFile string `env:"CERT_FILE" group:"auth"`
+	Syslog   bool   `env:"SYSLOG" group:"log" groupRule:"atMostOne" default:"false"`
+	Journal  bool   `env:"JOURNAL" group:"log" default:"false"`
+}
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	auth := []string{"CERT_FILE", "PASSWORD", "TOKEN"}
+	tests := []struct {
+		name string
+		vars MapLookuper
+		errs []error
+	}{
+		{
+			name: "one set",
+			vars: MapLookuper{"TOKEN": "t0k3n"},
+		},
+		{
+			name: "none set",
+			vars: MapLookuper{},
+			errs: []error{&GroupError{Group: "auth", Rule: GroupExactlyOne, Keys: auth}},
+		},
+		{
+			name: "several set",
+			vars: MapLookuper{"TOKEN": "t0k3n", "PASSWORD": "s3cr3t"},
+			errs: []error{&GroupError{Group: "auth", Rule: GroupExactlyOne, Keys: auth, Set: []string{"PASSWORD", "TOKEN"}}},
+		},
+		{
+			name: "defaults are not set",
+			vars: MapLookuper{"TOKEN": "t0k3n", "SYSLOG": "true"},
+		},
+		{
+			name: "at most one",
+			vars: MapLookuper{"TOKEN": "t0k3n", "SYSLOG": "true", "JOURNAL": "false"},
+			errs: []error{&GroupError{Group: "log", Rule: GroupAtMostOne, Keys: []string{"JOURNAL", "SYSLOG"}, Set: []string{"JOURNAL", "SYSLOG"}}},
+		},
+	}
+
+	for _, test := range tests {
+		var s groupSpec
+		results, _ := UnmarshalFrom(&s, test.vars)
+		assert.Equal(t, test.errs, results.Errors(), test.name)
+	}
+}
+
+func TestGroupsError(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Password string `env:"PASSWORD" group:"auth" groupRule:"atLeastOne"`
+		Token    string `env:"TOKEN" group:"auth"`
+	}
+	results, err := UnmarshalWithOptions(&s, Options{Lookuper: MapLookuper{}, Prefix: "ENV_CONFIG_"})
+	assert.EqualError(t, err, `group auth: at least one of "ENV_CONFIG_PASSWORD", "ENV_CONFIG_TOKEN" must be set, got none`)
+
+	var groupErr *GroupError
+	assert.True(t, errors.As(err, &groupErr))
+	assert.Equal(t, "auth", groupErr.Group)
+	assert.Equal(t, "ENV_CONFIG_PASSWORD,ENV_CONFIG_TOKEN", results[len(results)-1].KeyName)
+}
+
+func TestGroupsReusedStruct(t *testing.T) {
+	t.Parallel()
+
+	type credentials struct {
+		Password string `env:"PASSWORD" group:"auth" groupRule:"exactlyOne"`
+		Token    string `env:"TOKEN" group:"auth"`
+	}
+	var s struct {
+		Primary credentials `envPrefix:"PRIMARY_"`
+		Replica credentials `envPrefix:"REPLICA_"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{"PRIMARY_PASSWORD": "a", "REPLICA_PASSWORD": "b", "REPLICA_TOKEN": "c"})
+	assert.EqualError(t, err, `group auth: exactly one of "REPLICA_PASSWORD", "REPLICA_TOKEN" must be set, got "REPLICA_PASSWORD", "REPLICA_TOKEN"`)
+}
+
+func TestGroupsInvalidSpecification(t *testing.T) {
+	t.Parallel()
+
+	var missing struct {
+		Password string `env:"PASSWORD" group:"auth"`
+	}
+	_, err := UnmarshalFrom(&missing, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+
+	var conflicting struct {
+		Password string `env:"PASSWORD" group:"auth" groupRule:"exactlyOne"`
+		Token    string `env:"TOKEN" group:"auth" groupRule:"atMostOne"`
+	}
+	_, err = UnmarshalFrom(&conflicting, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+
+	var unknown struct {
+		Password string `env:"PASSWORD" group:"auth" groupRule:"one"`
+	}
+	_, err = UnmarshalFrom(&unknown, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+}
+
+func TestGroupsUsage(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Password string `env:"PASSWORD" group:"auth" groupRule:"exactlyOne" comment:"database password"`
+		Token    string `env:"TOKEN" group:"auth"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := Usagef(&s, buf, "{{range .}}{{usage_key .}}|{{usage_description .}}\n{{end}}")
+	require.NoError(t, err)
+	assert.Equal(t, "PASSWORD|database password (group auth)\n"+
+		"TOKEN|(group auth)\n", buf.String())
+
+	buf.Reset()
+	err = UsagefWithOptions(&s, buf, "{{range .}}{{usage_key .}}|{{usage_description .}}\n{{end}}", Options{Prefix: "APP_"})
+	require.NoError(t, err)
+	assert.Equal(t, "APP_PASSWORD|database password (group auth)\n"+
+		"APP_TOKEN|(group auth)\n", buf.String())
+}
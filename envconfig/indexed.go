@@ -0,0 +1,159 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxIndex limits indices of elements of indexed slices.
+const maxIndex = 1000
+
+// indexPlaceholder stands for the index in keys of elements of indexed slices in usage.
+const indexPlaceholder = "<N>"
+
+// isIndexed reports whether the slice is populated from variables with numeric suffixes.
+func isIndexed(ftype reflect.StructField) bool {
+	indexed, _ := strconv.ParseBool(ftype.Tag.Get("indexed"))
+	return indexed
+}
+
+// isStructElem reports whether elements of the slice or the map are structs
+// or pointers to structs that are populated field by field.
+func isStructElem(t reflect.Type) bool {
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && !implementsInterface(t.Elem())
+}
+
+// gatherIndexed gathers information about elements of the indexed slice.
+//
+// Elements of structs are read from variables prefixed with the `envPrefix` tag followed
+// by the index, e.g. MYAPP_UPSTREAM_0_URL. Other elements are read from variables named
+// after the `env` tag followed by the index, e.g. MYAPP_HOSTS_0. The length of the slice is
+// determined by the largest index found by the lookuper, so missing indices are reported as
+// missing variables. Variables with indices out of range are reported as failed.
+// The field is left intact if no indices in range are found. Without a lookuper a single
+// element with a placeholder index is described. The field is replaced by Store of the elements.
+func gatherIndexed(f reflect.Value, ftype reflect.StructField, prefix string, l Lookuper) ([]envVarInfo, error) {
+	if f.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("indexed tag is set on non-slice field %s: %w", ftype.Name, ErrInvalidSpecification)
+	}
+	isStruct := isStructElem(f.Type())
+
+	base := prefix + ftype.Tag.Get("envPrefix")
+	if isStruct && ftype.Tag.Get("envPrefix") == "" {
+		return nil, fmt.Errorf(`"envPrefix" tag is empty on indexed field %s: %w`, ftype.Name, ErrInvalidSpecification)
+	}
+	if !isStruct {
+		key := ftype.Tag.Get("env")
+		if key == "" {
+			return nil, fmt.Errorf(`"env" tag is empty on indexed field %s: %w`, ftype.Name, ErrInvalidSpecification)
+		}
+		base = prefix + key + "_"
+	}
+
+	var indices []string
+	var elems reflect.Value
+	var infos []envVarInfo
+	if l == nil {
+		indices = []string{indexPlaceholder}
+		elems = reflect.MakeSlice(f.Type(), 1, 1)
+	} else {
+		isFile, _ := strconv.ParseBool(ftype.Tag.Get("file"))
+		n, outOfRange, err := countIndices(l, base, isStruct, isFile)
+		if err != nil {
+			return nil, fmt.Errorf("indexed field %s: %w", ftype.Name, err)
+		}
+		// variables with indices out of range are reported as failed while other elements are populated
+		for _, key := range outOfRange {
+			infos = append(infos, envVarInfo{
+				Name:  fmt.Sprintf("%s[%s]", ftype.Name, key[len(base):]),
+				Key:   key,
+				Field: reflect.New(f.Type().Elem()).Elem(),
+				Err:   fmt.Errorf("indexed field %s: index of %s is out of range, must be less than %d", ftype.Name, key, maxIndex),
+			})
+		}
+		if n == 0 {
+			return infos, nil
+		}
+		for i := 0; i < n; i++ {
+			indices = append(indices, strconv.Itoa(i))
+		}
+		elems = reflect.MakeSlice(f.Type(), n, n)
+	}
+
+	// elements are gathered into a new slice which replaces the field only when unmarshaling
+	store := func() { f.Set(elems) }
+	for i, index := range indices {
+		elem := elems.Index(i)
+		if isStruct {
+			elem = followPointerChain(elem)
+			elemInfos, err := gatherInfo(elem.Addr().Interface(), base+index+"_", l)
+			if err != nil {
+				return nil, err
+			}
+			addStore(elemInfos, store)
+			infos = append(infos, elemInfos...)
+			continue
+		}
+
+		info, err := createEnvVarInfo(elem, ftype, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("struct field %s: %v: %w", ftype.Name, err, ErrInvalidSpecification)
+		}
+		info.Name = fmt.Sprintf("%s[%s]", ftype.Name, index)
+		info.Key = base + index
+		info.Aliases = nil
+		info.Store = store
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// countIndices returns the number of elements of the indexed slice and keys of variables
+// with indices out of range given keys of variables listed by the lookuper.
+func countIndices(l Lookuper, base string, isStruct, isFile bool) (int, []string, error) {
+	keys, ok := listKeys(l)
+	if !ok {
+		return 0, nil, fmt.Errorf("lookuper %T must implement Lister", l)
+	}
+
+	n := 0
+	var outOfRange []string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, base) {
+			continue
+		}
+		rest := key[len(base):]
+		end := 0
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		index, suffix := rest[:end], rest[end:]
+
+		switch {
+		case isStruct && strings.HasPrefix(suffix, "_"):
+		case !isStruct && (suffix == "" || isFile && suffix == fileSuffix):
+		default:
+			continue
+		}
+		// indices with leading zeros are not recognized
+		if index == "" || len(index) > 1 && index[0] == '0' {
+			continue
+		}
+		// a stray variable with a huge index must not allocate a huge slice
+		i, err := strconv.Atoi(index)
+		if err != nil || i >= maxIndex {
+			outOfRange = append(outOfRange, key)
+			continue
+		}
+		if i >= n {
+			n = i + 1
+		}
+	}
+	return n, outOfRange, nil
+}
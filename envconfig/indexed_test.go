@@ -0,0 +1,198 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upstream struct {
+	URL    string `env:"URL"`
+	Weight int    `env:"WEIGHT" default:"1"`
+}
+
+func (u *upstream) Validate() error {
+	if u.Weight < 0 {
+		return errors.New("weight must not be negative")
+	}
+	return nil
+}
+
+type indexedSpec struct {
+	Hosts     []string    `env:"HOSTS" indexed:"true"`
+	Upstreams []upstream  `envPrefix:"UPSTREAM_" indexed:"true"`
+	Backups   []*upstream `envPrefix:"BACKUP_" indexed:"true"`
+}
+
+func TestIndexed(t *testing.T) {
+	t.Parallel()
+
+	s := indexedSpec{Hosts: []string{"localhost"}}
+	results, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"MYAPP_UPSTREAM_0_URL":    "http://a",
+			"MYAPP_UPSTREAM_0_WEIGHT": "3",
+			"MYAPP_UPSTREAM_1_URL":    "http://b",
+			"MYAPP_BACKUP_0_URL":      "http://c",
+			"MYAPP_UPSTREAM_01_URL":   "http://d",
+			"MYAPP_HOSTSX_0":          "ignored",
+		},
+		Prefix: "MYAPP_",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"localhost"}, s.Hosts)
+	assert.Equal(t, []upstream{{URL: "http://a", Weight: 3}, {URL: "http://b", Weight: 1}}, s.Upstreams)
+	assert.Equal(t, []*upstream{{URL: "http://c", Weight: 1}}, s.Backups)
+
+	keys := make([]string, len(results))
+	for i, result := range results {
+		keys[i] = result.KeyName
+	}
+	assert.Equal(t, []string{
+		"MYAPP_BACKUP_0_URL",
+		"MYAPP_BACKUP_0_WEIGHT",
+		"MYAPP_UPSTREAM_0_URL",
+		"MYAPP_UPSTREAM_0_WEIGHT",
+		"MYAPP_UPSTREAM_1_URL",
+		"MYAPP_UPSTREAM_1_WEIGHT",
+	}, keys)
+}
+
+func TestIndexedScalars(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Hosts []string `env:"HOSTS" indexed:"true" pattern:"^[a-z]+$"`
+	}
+	results, err := UnmarshalFrom(&s, MapLookuper{"HOSTS_0": "a", "HOSTS_2": "c"})
+	assert.Equal(t, []string{"a", "", "c"}, s.Hosts)
+	assert.EqualError(t, err, `env variable is not set: "HOSTS_1"`)
+	assert.Equal(t, "Hosts[1]", results[1].FieldName)
+
+	_, err = UnmarshalFrom(&s, MapLookuper{"HOSTS_0": "a", "HOSTS_1": "B"})
+	assert.EqualError(t, err, `env variable "HOSTS_1" is invalid: must match pattern ^[a-z]+$`)
+}
+
+func TestIndexedValidate(t *testing.T) {
+	t.Parallel()
+
+	var s indexedSpec
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"UPSTREAM_0_URL":    "http://a",
+		"UPSTREAM_1_URL":    "http://b",
+		"UPSTREAM_1_WEIGHT": "-1",
+	})
+	assert.EqualError(t, err, "validate Upstreams[1]: weight must not be negative")
+}
+
+func TestIndexedStrict(t *testing.T) {
+	t.Parallel()
+
+	var s indexedSpec
+	_, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"MYAPP_UPSTREAM_0_URL":  "http://a",
+			"MYAPP_UPSTREAM_0_URLS": "http://b",
+		},
+		Prefix: "MYAPP_",
+		Strict: true,
+	})
+	assert.EqualError(t, err, `unknown env variable is set: "MYAPP_UPSTREAM_0_URLS"; did you mean "MYAPP_UPSTREAM_0_URL"?`)
+}
+
+func TestIndexedInvalidSpecification(t *testing.T) {
+	t.Parallel()
+
+	var notSlice struct {
+		Host string `env:"HOST" indexed:"true"`
+	}
+	_, err := UnmarshalFrom(&notSlice, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+
+	var noKey struct {
+		Hosts []string `indexed:"true"`
+	}
+	_, err = UnmarshalFrom(&noKey, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+
+	var noPrefix struct {
+		Upstreams []upstream `indexed:"true"`
+	}
+	_, err = UnmarshalFrom(&noPrefix, MapLookuper{"0_URL": "http://a"})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+	assert.Nil(t, noPrefix.Upstreams)
+
+	var s indexedSpec
+	_, err = UnmarshalFrom(&s, lookupFunc(func(string) (string, bool) { return "", false }))
+	assert.EqualError(t, err, "indexed field Hosts: lookuper envconfig.lookupFunc must implement Lister")
+}
+
+func TestIndexedUsage(t *testing.T) {
+	t.Parallel()
+
+	var s indexedSpec
+	buf := new(bytes.Buffer)
+	err := Usagef(&s, buf, "{{range .}}{{usage_key .}}|{{usage_type .}}\n{{end}}")
+	require.NoError(t, err)
+	assert.Equal(t, "BACKUP_<N>_URL|String\n"+
+		"BACKUP_<N>_WEIGHT|Integer\n"+
+		"HOSTS_<N>|String\n"+
+		"UPSTREAM_<N>_URL|String\n"+
+		"UPSTREAM_<N>_WEIGHT|Integer\n", buf.String())
+	assert.Nil(t, s.Hosts)
+}
+
+func TestIndexedOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	for _, index := range []string{"1000", "9999999999999", "99999999999999999999999"} {
+		var s indexedSpec
+		results, err := UnmarshalFrom(&s, MapLookuper{
+			"HOSTS_0":           "a",
+			"HOSTS_" + index:    "b",
+			"UPSTREAM_0_URL":    "http://a",
+			"UPSTREAM_0_WEIGHT": "x",
+		})
+		var multiErr *MultiError
+		require.True(t, errors.As(err, &multiErr), index)
+		require.Len(t, multiErr.Errors, 2, index)
+		assert.EqualError(t, multiErr.Errors[0], "indexed field Hosts: index of HOSTS_"+index+" is out of range, must be less than 1000", index)
+		assert.Equal(t, []string{"a"}, s.Hosts, index)
+
+		var failed []string
+		for _, result := range results {
+			if result.Err != nil {
+				failed = append(failed, result.FieldName)
+			}
+		}
+		assert.Equal(t, []string{"Hosts[" + index + "]", "Weight"}, failed, index)
+	}
+}
+
+func TestIndexedFindUnknownAfterUnmarshal(t *testing.T) {
+	var s struct {
+		Hosts     []string   `env:"PRB_HOSTS" indexed:"true"`
+		Upstreams []upstream `envPrefix:"PRB_UPSTREAM_" indexed:"true"`
+	}
+
+	os.Clearenv()
+	os.Setenv("PRB_HOSTS_0", "a")
+	os.Setenv("PRB_HOSTS_1", "b")
+	os.Setenv("PRB_UPSTREAM_0_URL", "http://a")
+	os.Setenv("PRB_UNKNOWN", "true")
+
+	_, err := Unmarshal(&s)
+	require.NoError(t, err)
+
+	unknownVars, err := FindUnknownEnvVariablesByPrefix("PRB_", &s)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PRB_UNKNOWN"}, unknownVars)
+
+	assert.Equal(t, []string{"a", "b"}, s.Hosts)
+	assert.Equal(t, []upstream{{URL: "http://a", Weight: 1}}, s.Upstreams)
+}
@@ -17,6 +17,12 @@ type Setter interface {
 	Set(value string) error
 }
 
+// Validator is implemented by specifications and nested structs that validate
+// themselves after all fields are populated, e.g. to check rules spanning several fields.
+type Validator interface {
+	Validate() error
+}
+
 var (
 	decoderType           = reflect.TypeOf((*Decoder)(nil)).Elem()
 	setterType            = reflect.TypeOf((*Setter)(nil)).Elem()
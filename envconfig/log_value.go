@@ -0,0 +1,50 @@
+//go:build go1.21
+// +build go1.21
+
+package envconfig
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer. Results are logged as a group
+// with a nested group per env variable. Values of sensitive fields are redacted.
+func (f FieldUnmarshalResults) LogValue() slog.Value {
+	attrs := make([]slog.Attr, len(f))
+	for i, result := range f {
+		r := result.toJSON()
+		fields := []slog.Attr{
+			slog.String("field", r.Field),
+			slog.String("type", r.Type),
+			slog.String("value", r.Value),
+		}
+		if r.RawValue != "" {
+			fields = append(fields, slog.String("raw_value", r.RawValue))
+		}
+		if r.Sensitive {
+			fields = append(fields, slog.Bool("sensitive", true))
+		}
+		if r.Source != "" {
+			fields = append(fields, slog.String("source", r.Source))
+		}
+		if r.File != "" {
+			fields = append(fields, slog.String("file", r.File))
+		}
+		if r.Default != "" {
+			fields = append(fields, slog.String("default", r.Default))
+		}
+		if r.Warning != "" {
+			fields = append(fields, slog.String("warning", r.Warning))
+		}
+		if r.NotSet {
+			fields = append(fields, slog.Bool("not_set", true))
+		}
+		if r.Error != "" {
+			fields = append(fields, slog.String("error", r.Error))
+		}
+		key := r.Key
+		if key == "" {
+			key = r.Field
+		}
+		attrs[i] = slog.Attr{Key: key, Value: slog.GroupValue(fields...)}
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,46 @@
+//go:build go1.21
+// +build go1.21
+
+package envconfig
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldUnmarshalResults_LogValue(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Port     int    `env:"ENV_CONFIG_PORT"`
+		Password string `env:"ENV_CONFIG_PASSWORD" sensitive:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PASSWORD": "s3cr3t",
+	})
+	require.Error(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("config", "results", results)
+
+	require.JSONEq(t, `{
+		"level": "INFO",
+		"msg": "config",
+		"results": {
+			"ENV_CONFIG_PASSWORD": {"field": "Password", "type": "string", "value": "******", "sensitive": true, "source": "map"},
+			"ENV_CONFIG_PORT": {"field": "Port", "type": "int", "value": "", "error": "env variable is not set: \"ENV_CONFIG_PORT\""}
+		}
+	}`, buf.String())
+}
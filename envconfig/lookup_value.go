@@ -0,0 +1,94 @@
+package envconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// fileSuffix is appended to the key of the variable that holds a path
+// to the file with the value when the `file` tag is set.
+const fileSuffix = "_FILE"
+
+// lookupResult describes the value of the variable and where it was found.
+// NotSet is true when the optional variable was not found. RawValue is the value
+// before expansion of references to other variables.
+type lookupResult struct {
+	Value    string
+	RawValue string
+	Source   string
+	File     string
+	Warning  string
+	NotSet   bool
+}
+
+// IsSet reports whether the value was supplied by a source rather than the `default` tag.
+func (r lookupResult) IsSet() bool {
+	return r.Source != "" && r.Source != SourceDefault
+}
+
+// Keys returns all keys the value of the variable may be read from.
+func (info *envVarInfo) Keys() []string {
+	var keys []string
+	for _, key := range append([]string{info.Key}, info.Aliases...) {
+		keys = append(keys, key)
+		if info.IsFile {
+			keys = append(keys, key+fileSuffix)
+		}
+	}
+	return keys
+}
+
+// LookupValue retrieves the value of the variable using the specified lookuper.
+// The key takes precedence over aliases which are tried in order.
+// If the variable is not set and the `file` tag is set, the value is read from
+// the file specified by the variable with the _FILE suffix.
+// It falls back to the default value if one is set.
+// Optional, conditional or grouped variable without the default value is reported as not set.
+func (info *envVarInfo) LookupValue(l Lookuper) (lookupResult, error) {
+	if info.Err != nil {
+		return lookupResult{}, info.Err
+	}
+	if info.Key == "" {
+		return lookupResult{}, fmt.Errorf(`"env" tag is empty on struct field: %s`, info.Name)
+	}
+
+	for _, key := range append([]string{info.Key}, info.Aliases...) {
+		lookup, ok, err := info.lookupKey(l, key)
+		if !ok {
+			continue
+		}
+		if key != info.Key {
+			lookup.Warning = fmt.Sprintf("%s is deprecated, use %s", key, info.Key)
+		}
+		return lookup, err
+	}
+
+	switch {
+	case info.IsDefaultSet:
+		return lookupResult{Value: info.Default, Source: SourceDefault}, nil
+	case info.IsOptional, info.Conditions.IsConditional(), info.Group != "":
+		return lookupResult{NotSet: true}, nil
+	}
+	return lookupResult{}, &MissingVariableError{Key: info.Key}
+}
+
+func (info *envVarInfo) lookupKey(l Lookuper, key string) (lookupResult, bool, error) {
+	if value, source, ok := lookupSource(l, key); ok {
+		return lookupResult{Value: value, Source: source}, true, nil
+	}
+
+	if !info.IsFile {
+		return lookupResult{}, false, nil
+	}
+	path, source, ok := lookupSource(l, key+fileSuffix)
+	if !ok {
+		return lookupResult{}, false, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return lookupResult{Source: source, File: path}, true, fmt.Errorf("read %s%s: %w", key, fileSuffix, err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	return lookupResult{Value: value, Source: source, File: path}, true, nil
+}
@@ -0,0 +1,199 @@
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Lookuper is the interface that wraps the Lookup method.
+//
+// Lookup retrieves the value of the variable named by the key. If the variable
+// is present the value (which may be empty) is returned and the boolean is true.
+// Otherwise the returned value will be empty and the boolean will be false.
+type Lookuper interface {
+	Lookup(key string) (string, bool)
+}
+
+// Lister is implemented by lookupers that can list keys of all their variables.
+// It is required to detect unknown variables in strict mode.
+type Lister interface {
+	Keys() []string
+}
+
+// OsLookuper looks up variables in the environment of the current process.
+type OsLookuper struct{}
+
+// Lookup implements Lookuper.
+func (OsLookuper) Lookup(key string) (string, bool) {
+	// `os.Getenv` cannot differentiate between an explicitly set empty value
+	// and an unset value. `os.LookupEnv` is preferred to `syscall.Getenv`.
+	return os.LookupEnv(key)
+}
+
+// Keys implements Lister.
+func (OsLookuper) Keys() []string {
+	return EnvironLookuper(os.Environ()).Keys()
+}
+
+// MapLookuper looks up variables in a map.
+type MapLookuper map[string]string
+
+// Lookup implements Lookuper.
+func (m MapLookuper) Lookup(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+// Keys implements Lister. Keys are sorted.
+func (m MapLookuper) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvironLookuper looks up variables in a slice of "key=value" strings
+// in the form returned by os.Environ. When a key occurs more than once
+// the last occurrence wins.
+type EnvironLookuper []string
+
+// Lookup implements Lookuper.
+func (e EnvironLookuper) Lookup(key string) (string, bool) {
+	for i := len(e) - 1; i >= 0; i-- {
+		kv := strings.SplitN(e[i], "=", 2)
+		if kv[0] != key {
+			continue
+		}
+		if len(kv) == 1 {
+			return "", true
+		}
+		return kv[1], true
+	}
+	return "", false
+}
+
+// Keys implements Lister. Keys are listed in order of their first occurrence.
+func (e EnvironLookuper) Keys() []string {
+	keys := make([]string, 0, len(e))
+	seen := make(map[string]struct{}, len(e))
+	for _, env := range e {
+		key := strings.SplitN(env, "=", 2)[0]
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SourceDefault is the name of the source reported when
+// a value was taken from the `default` tag.
+const SourceDefault = "default"
+
+// Name returns a name of the source reported in FieldUnmarshalResult.Source.
+func (OsLookuper) Name() string { return "env" }
+
+// Name returns a name of the source reported in FieldUnmarshalResult.Source.
+func (MapLookuper) Name() string { return "map" }
+
+// Name returns a name of the source reported in FieldUnmarshalResult.Source.
+func (EnvironLookuper) Name() string { return "environ" }
+
+type namedLookuper struct {
+	Lookuper
+	name string
+}
+
+// NamedLookuper returns a lookuper which reports the specified name as
+// a source of values found in the underlying lookuper.
+func NamedLookuper(name string, l Lookuper) Lookuper {
+	return namedLookuper{Lookuper: l, name: name}
+}
+
+func (n namedLookuper) Name() string { return n.name }
+
+// listKeys lists keys of the underlying lookuper if it implements Lister.
+func (n namedLookuper) listKeys() ([]string, bool) {
+	return listKeys(n.Lookuper)
+}
+
+type chainLookuper []Lookuper
+
+// ChainLookuper returns a lookuper which resolves each key through the specified
+// lookupers in order. The first lookuper that has the key supplies the value.
+func ChainLookuper(lookupers ...Lookuper) Lookuper {
+	return chainLookuper(lookupers)
+}
+
+// Lookup implements Lookuper.
+func (c chainLookuper) Lookup(key string) (string, bool) {
+	value, _, ok := c.lookupSource(key)
+	return value, ok
+}
+
+// listKeys lists keys of all lookupers if each of them implements Lister.
+func (c chainLookuper) listKeys() ([]string, bool) {
+	var keys []string
+	seen := make(map[string]struct{})
+	for _, l := range c {
+		lkeys, ok := listKeys(l)
+		if !ok {
+			return nil, false
+		}
+		for _, key := range lkeys {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	return keys, true
+}
+
+func (c chainLookuper) lookupSource(key string) (string, string, bool) {
+	for _, l := range c {
+		if value, source, ok := lookupSource(l, key); ok {
+			return value, source, true
+		}
+	}
+	return "", "", false
+}
+
+// lookupSource retrieves the value of the variable and the name of the source that supplied it.
+func lookupSource(l Lookuper, key string) (value, source string, ok bool) {
+	if c, isChain := l.(chainLookuper); isChain {
+		return c.lookupSource(key)
+	}
+	value, ok = l.Lookup(key)
+	if !ok {
+		return "", "", false
+	}
+	return value, sourceName(l), true
+}
+
+// listKeys lists keys of the lookuper. It returns false if the lookuper does not implement Lister.
+// Wrappers of other lookupers can list keys only if all of the wrapped lookupers do.
+func listKeys(l Lookuper) ([]string, bool) {
+	if w, ok := l.(interface{ listKeys() ([]string, bool) }); ok {
+		return w.listKeys()
+	}
+	lister, ok := l.(Lister)
+	if !ok {
+		return nil, false
+	}
+	return lister.Keys(), true
+}
+
+// sourceName returns the name of the lookuper or its type if it has no name.
+func sourceName(l Lookuper) string {
+	if n, ok := l.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", l)
+}
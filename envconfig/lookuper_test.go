@@ -0,0 +1,145 @@
+package envconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapLookuper(t *testing.T) {
+	t.Parallel()
+
+	l := MapLookuper{"FOO": "bar", "EMPTY": ""}
+
+	value, ok := l.Lookup("FOO")
+	assert.True(t, ok)
+	assert.Equal(t, "bar", value)
+
+	value, ok = l.Lookup("EMPTY")
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+
+	_, ok = l.Lookup("MISSING")
+	assert.False(t, ok)
+}
+
+func TestEnvironLookuper(t *testing.T) {
+	t.Parallel()
+
+	l := EnvironLookuper{"FOO=bar", "EMPTY=", "EQ=a=b", "FOO=baz"}
+
+	value, ok := l.Lookup("FOO")
+	assert.True(t, ok)
+	assert.Equal(t, "baz", value)
+
+	value, ok = l.Lookup("EMPTY")
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+
+	value, ok = l.Lookup("EQ")
+	assert.True(t, ok)
+	assert.Equal(t, "a=b", value)
+
+	_, ok = l.Lookup("FO")
+	assert.False(t, ok)
+}
+
+func TestUnmarshalFrom(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Port    int      `env:"ENV_CONFIG_PORT"`
+		Users   []string `env:"ENV_CONFIG_USERS"`
+		Default string   `env:"ENV_CONFIG_DEFAULT" default:"foobar"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PORT":  "8080",
+		"ENV_CONFIG_USERS": "rob,ken",
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, 8080, s.Port)
+	assert.Equal(t, []string{"rob", "ken"}, s.Users)
+	assert.Equal(t, "foobar", s.Default)
+}
+
+func TestUnmarshalFromMissing(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Port int `env:"ENV_CONFIG_PORT"`
+	}
+
+	_, err := UnmarshalFrom(&s, EnvironLookuper{"ENV_CONFIG_PORTS=8080"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ENV_CONFIG_PORT")
+}
+
+func TestChainLookuperSource(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Host  string `env:"ENV_CONFIG_HOST"`
+		Port  int    `env:"ENV_CONFIG_PORT"`
+		Debug bool   `env:"ENV_CONFIG_DEBUG" default:"false"`
+		User  string `env:"ENV_CONFIG_USER"`
+	}
+
+	l := ChainLookuper(
+		NamedLookuper("cli", MapLookuper{"ENV_CONFIG_PORT": "9090"}),
+		NamedLookuper(".env", MapLookuper{"ENV_CONFIG_PORT": "8080", "ENV_CONFIG_HOST": "localhost"}),
+		EnvironLookuper{"ENV_CONFIG_HOST=example.com", "ENV_CONFIG_USER=Kelsey"},
+	)
+
+	results, err := UnmarshalFrom(&s, l)
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", s.Host)
+	assert.Equal(t, 9090, s.Port)
+	assert.False(t, s.Debug)
+	assert.Equal(t, "Kelsey", s.User)
+
+	sources := make(map[string]string, len(results))
+	for _, result := range results {
+		sources[result.KeyName] = result.Source
+	}
+	assert.Equal(t, map[string]string{
+		"ENV_CONFIG_HOST":  ".env",
+		"ENV_CONFIG_PORT":  "cli",
+		"ENV_CONFIG_DEBUG": SourceDefault,
+		"ENV_CONFIG_USER":  "environ",
+	}, sources)
+}
+
+func TestLookuperKeys(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"A", "B"}, MapLookuper{"B": "", "A": ""}.Keys())
+	assert.Equal(t, []string{"B", "A"}, EnvironLookuper{"B=1", "A=2", "B=3"}.Keys())
+
+	l := ChainLookuper(
+		NamedLookuper("cli", MapLookuper{"C": "", "A": ""}),
+		EnvironLookuper{"B=1", "A=2"},
+	)
+	keys, ok := listKeys(l)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"A", "C", "B"}, keys)
+
+	_, ok = listKeys(lookupFunc(nil))
+	assert.False(t, ok)
+
+	// wrappers cannot list keys of lookupers that do not implement Lister
+	_, ok = listKeys(ChainLookuper(MapLookuper{"A": ""}, lookupFunc(nil)))
+	assert.False(t, ok)
+	_, ok = listKeys(NamedLookuper("cli", lookupFunc(nil)))
+	assert.False(t, ok)
+}
+
+type lookupFunc func(key string) (string, bool)
+
+func (f lookupFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
@@ -0,0 +1,160 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// namePlaceholder stands for the map key in keys of entries of maps populated from a prefix in usage.
+const namePlaceholder = "<NAME>"
+
+// gatherMapPrefix gathers information about entries of the map with the `envMapPrefix` tag.
+//
+// Every variable starting with the prefix becomes an entry of the map with the rest
+// of the key as the map key, e.g. MYAPP_LABEL_team=core with the prefix MYAPP_LABEL_
+// results in the "team" entry. The field is replaced by Store of the entries
+// and left intact if no such variables are found.
+// Without a lookuper a single entry with a placeholder name is described.
+// Maps of structs are handled by gatherMapOfStructs.
+func gatherMapPrefix(f reflect.Value, ftype reflect.StructField, prefix string, l Lookuper) ([]envVarInfo, error) {
+	if f.Kind() != reflect.Map || f.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("envMapPrefix tag is set on field %s which is not a map with string keys: %w", ftype.Name, ErrInvalidSpecification)
+	}
+	mapPrefix := ftype.Tag.Get("envMapPrefix")
+	if mapPrefix == "" {
+		return nil, fmt.Errorf("envMapPrefix tag is empty on field %s: %w", ftype.Name, ErrInvalidSpecification)
+	}
+	mapPrefix = prefix + mapPrefix
+	if isStructElem(f.Type()) {
+		return gatherMapOfStructs(f, ftype, mapPrefix, l)
+	}
+
+	names := []string{namePlaceholder}
+	// entries are gathered into a new map which replaces the field only when unmarshaling
+	m := reflect.MakeMap(f.Type())
+	if l != nil {
+		keys, ok := listKeys(l)
+		if !ok {
+			return nil, fmt.Errorf("map field %s: lookuper %T must implement Lister", ftype.Name, l)
+		}
+		names = names[:0]
+		for _, key := range keys {
+			if strings.HasPrefix(key, mapPrefix) && len(key) > len(mapPrefix) {
+				names = append(names, key[len(mapPrefix):])
+			}
+		}
+		if len(names) == 0 {
+			return nil, nil
+		}
+		sort.Strings(names)
+	}
+
+	infos := make([]envVarInfo, 0, len(names))
+	for _, name := range names {
+		elem := reflect.New(f.Type().Elem()).Elem()
+		info, err := createEnvVarInfo(elem, ftype, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("struct field %s: %v: %w", ftype.Name, err, ErrInvalidSpecification)
+		}
+		info.Name = fmt.Sprintf("%s[%s]", ftype.Name, name)
+		info.Key = mapPrefix + name
+		info.Aliases = nil
+		key := reflect.ValueOf(name).Convert(f.Type().Key())
+		info.Store = func() {
+			m.SetMapIndex(key, elem)
+			f.Set(m)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// gatherMapOfStructs gathers information about fields of structs in the map with the `envMapPrefix` tag.
+//
+// Names of instances are discovered from keys of variables starting with the prefix: the rest
+// of the key must end with a key of any of the fields of the struct, e.g. MYAPP_DB_ORDERS_HOST
+// with the prefix MYAPP_DB_ and a field with `env:"HOST"` results in the "ORDERS" instance.
+// The longest matching key wins. Each instance is populated with the usual tags relative
+// to MYAPP_DB_<NAME>_, so defaults and required variables apply per instance.
+// The field is replaced by Store of fields of the instances.
+func gatherMapOfStructs(f reflect.Value, ftype reflect.StructField, mapPrefix string, l Lookuper) ([]envVarInfo, error) {
+	elemType := f.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	names := []string{namePlaceholder}
+	// instances are gathered into a new map which replaces the field only when unmarshaling
+	m := reflect.MakeMap(f.Type())
+	if l != nil {
+		keys, ok := listKeys(l)
+		if !ok {
+			return nil, fmt.Errorf("map field %s: lookuper %T must implement Lister", ftype.Name, l)
+		}
+		fieldInfos, err := gatherInfo(reflect.New(elemType).Interface(), "", nil)
+		if err != nil {
+			return nil, err
+		}
+		names = instanceNames(keys, mapPrefix, fieldInfos)
+		if len(names) == 0 {
+			return nil, nil
+		}
+	}
+
+	var infos []envVarInfo
+	for _, name := range names {
+		instance := reflect.New(elemType)
+		instanceInfos, err := gatherInfo(instance.Interface(), mapPrefix+name+"_", l)
+		if err != nil {
+			return nil, err
+		}
+
+		key := reflect.ValueOf(name).Convert(f.Type().Key())
+		value := instance
+		if !isPtr {
+			// the map holds a copy of the struct which is updated after each field is unmarshaled
+			value = instance.Elem()
+		}
+		addStore(instanceInfos, func() {
+			m.SetMapIndex(key, value)
+			f.Set(m)
+		})
+		infos = append(infos, instanceInfos...)
+	}
+	return infos, nil
+}
+
+// instanceNames returns sorted names of instances of structs in the map
+// given keys of variables and information about fields of the struct.
+func instanceNames(keys []string, mapPrefix string, infos []envVarInfo) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, mapPrefix) {
+			continue
+		}
+		rest := key[len(mapPrefix):]
+
+		var name string
+		for _, info := range infos {
+			for _, k := range info.Keys() {
+				n := len(rest) - len(k) - 1
+				if k == "" || n <= 0 || !strings.HasSuffix(rest, "_"+k) {
+					continue
+				}
+				if name == "" || n < len(name) {
+					name = rest[:n]
+				}
+			}
+		}
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,237 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapPrefix(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Labels   map[string]string `envMapPrefix:"LABEL_"`
+		Headers  map[string]string `envMapPrefix:"HEADER_"`
+		Limits   map[string]int    `envMapPrefix:"LIMIT_" min:"1"`
+		Timeouts map[string]int    `envMapPrefix:"TIMEOUT_"`
+	}
+	s.Timeouts = map[string]int{"read": 5}
+
+	results, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"MYAPP_LABEL_team":            "core",
+			"MYAPP_LABEL_app":             "web",
+			"MYAPP_LABEL_":                "ignored",
+			"MYAPP_HEADER_Accept":         "text/plain, text/html",
+			"MYAPP_HEADER_X-Forwarded-To": "http://example.com:8080",
+			"MYAPP_LIMIT_cpu":             "2",
+		},
+		Prefix: "MYAPP_",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"team": "core", "app": "web"}, s.Labels)
+	assert.Equal(t, map[string]string{"Accept": "text/plain, text/html", "X-Forwarded-To": "http://example.com:8080"}, s.Headers)
+	assert.Equal(t, map[string]int{"cpu": 2}, s.Limits)
+	assert.Equal(t, map[string]int{"read": 5}, s.Timeouts)
+
+	require.Len(t, results, 5)
+	assert.Equal(t, "MYAPP_HEADER_Accept", results[0].KeyName)
+	assert.Equal(t, "Headers[Accept]", results[0].FieldName)
+	assert.Equal(t, "string", results[0].TypeName)
+}
+
+func TestMapPrefixErrors(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Limits map[string]int `envMapPrefix:"LIMIT_" min:"1"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{"LIMIT_cpu": "0", "LIMIT_mem": "x"})
+	assert.EqualError(t, err, `env variable "LIMIT_cpu" is invalid: must be at least 1`+"\n"+
+		`assigning LIMIT_mem="x" to Limits[mem] type int: strconv.ParseInt: parsing "x": invalid syntax`)
+
+	var notMap struct {
+		Labels []string `envMapPrefix:"LABEL_"`
+	}
+	_, err = UnmarshalFrom(&notMap, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+
+	var emptyPrefix struct {
+		Labels map[string]string `envMapPrefix:""`
+	}
+	_, err = UnmarshalFrom(&emptyPrefix, MapLookuper{})
+	assert.True(t, errors.Is(err, ErrInvalidSpecification))
+}
+
+func TestMapPrefixUnknown(t *testing.T) {
+	var s struct {
+		Port   int               `env:"ENV_CONFIG_MP_PORT" default:"8080"`
+		Labels map[string]string `envMapPrefix:"ENV_CONFIG_MP_LABEL_"`
+	}
+
+	os.Clearenv()
+	os.Setenv("ENV_CONFIG_MP_LABEL_team", "core")
+	os.Setenv("ENV_CONFIG_MP_PROT", "9090")
+
+	unknownVars, err := FindUnknownEnvVariablesByPrefix("ENV_CONFIG_MP_", &s)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ENV_CONFIG_MP_PROT"}, unknownVars)
+
+	_, err = UnmarshalWithOptions(&s, Options{Prefix: "ENV_CONFIG_MP_", Strict: true})
+	require.Error(t, err)
+
+	os.Unsetenv("ENV_CONFIG_MP_PROT")
+	_, err = Unmarshal(&s)
+	require.NoError(t, err)
+
+	_, err = FindUnknownEnvVariablesByPrefix("ENV_CONFIG_MP_", &s)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "core"}, s.Labels)
+}
+
+func TestMapPrefixUsage(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Labels map[string]int `envMapPrefix:"LABEL_"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := UsagefWithOptions(&s, buf, "{{range .}}{{usage_key .}}|{{usage_type .}}\n{{end}}", Options{Prefix: "MYAPP_"})
+	require.NoError(t, err)
+	assert.Equal(t, "MYAPP_LABEL_<NAME>|Integer\n", buf.String())
+	assert.Nil(t, s.Labels)
+}
+
+type tenantDB struct {
+	Host     string `env:"HOST"`
+	Port     int    `env:"PORT" default:"5432"`
+	User     string `env:"USER" optional:"true"`
+	ReadHost string `env:"READ_HOST" optional:"true"`
+}
+
+func (db tenantDB) Validate() error {
+	if db.Port == 0 {
+		return errors.New("port must not be zero")
+	}
+	return nil
+}
+
+func TestMapOfStructs(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		DBs     map[string]tenantDB  `envMapPrefix:"DB_"`
+		Caches  map[string]*tenantDB `envMapPrefix:"CACHE_"`
+		Unknown map[string]tenantDB  `envMapPrefix:"NONE_"`
+	}
+
+	results, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"MYAPP_DB_ORDERS_HOST":       "orders.db",
+			"MYAPP_DB_ORDERS_READ_HOST":  "replica.orders.db",
+			"MYAPP_DB_USERS_EU_HOST":     "users.db",
+			"MYAPP_DB_USERS_EU_PORT":     "6432",
+			"MYAPP_CACHE_SESSIONS_HOST":  "sessions.cache",
+			"MYAPP_CACHE_SESSIONS_OTHER": "ignored",
+		},
+		Prefix: "MYAPP_",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]tenantDB{
+		"ORDERS":   {Host: "orders.db", Port: 5432, ReadHost: "replica.orders.db"},
+		"USERS_EU": {Host: "users.db", Port: 6432},
+	}, s.DBs)
+	assert.Equal(t, map[string]*tenantDB{"SESSIONS": {Host: "sessions.cache", Port: 5432}}, s.Caches)
+	assert.Nil(t, s.Unknown)
+
+	require.Len(t, results, 12)
+	assert.Equal(t, "MYAPP_DB_ORDERS_PORT", results[5].KeyName)
+	assert.Equal(t, SourceDefault, results[5].Source)
+}
+
+func TestMapOfStructsErrors(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		DBs map[string]tenantDB `envMapPrefix:"DB_"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"DB_ORDERS_HOST": "orders.db",
+		"DB_USERS_PORT":  "6432",
+		"DB_EMPTY_HOST":  "empty.db",
+		"DB_EMPTY_PORT":  "0",
+	})
+	assert.EqualError(t, err, `env variable is not set: "DB_USERS_HOST"`)
+
+	_, err = UnmarshalFrom(&s, MapLookuper{
+		"DB_ORDERS_HOST": "orders.db",
+		"DB_EMPTY_HOST":  "empty.db",
+		"DB_EMPTY_PORT":  "0",
+	})
+	assert.EqualError(t, err, "validate DBs[EMPTY]: port must not be zero")
+}
+
+func TestMapOfStructsStrict(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		DBs map[string]tenantDB `envMapPrefix:"DB_"`
+	}
+
+	_, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"MYAPP_DB_ORDERS_HOST":  "orders.db",
+			"MYAPP_DB_ORDERS_HOSTT": "orders.db",
+		},
+		Prefix: "MYAPP_",
+		Strict: true,
+	})
+	assert.EqualError(t, err, `unknown env variable is set: "MYAPP_DB_ORDERS_HOSTT"; did you mean "MYAPP_DB_ORDERS_HOST"?`)
+}
+
+func TestMapOfStructsUsage(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		DBs map[string]tenantDB `envMapPrefix:"DB_"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := Usagef(&s, buf, "{{range .}}{{usage_key .}}|{{usage_type .}}|{{usage_default .}}\n{{end}}")
+	require.NoError(t, err)
+	assert.Equal(t, "DB_<NAME>_HOST|String|\n"+
+		"DB_<NAME>_PORT|Integer|5432\n"+
+		"DB_<NAME>_READ_HOST|String|\n"+
+		"DB_<NAME>_USER|String|\n", buf.String())
+}
+
+func TestMapOfStructsFindUnknownAfterUnmarshal(t *testing.T) {
+	var s struct {
+		DBs    map[string]tenantDB  `envMapPrefix:"PRB_DB_"`
+		Caches map[string]*tenantDB `envMapPrefix:"PRB_CACHE_"`
+	}
+
+	os.Clearenv()
+	os.Setenv("PRB_DB_ORDERS_HOST", "orders.db")
+	os.Setenv("PRB_CACHE_SESSIONS_HOST", "sessions.cache")
+	os.Setenv("PRB_UNKNOWN", "true")
+
+	_, err := Unmarshal(&s)
+	require.NoError(t, err)
+
+	unknownVars, err := FindUnknownEnvVariablesByPrefix("PRB_", &s)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PRB_UNKNOWN"}, unknownVars)
+
+	assert.Equal(t, map[string]tenantDB{"ORDERS": {Host: "orders.db", Port: 5432}}, s.DBs)
+	assert.Equal(t, map[string]*tenantDB{"SESSIONS": {Host: "sessions.cache", Port: 5432}}, s.Caches)
+}
@@ -0,0 +1,30 @@
+package envconfig
+
+// Options configures UnmarshalWithOptions and UsagefWithOptions.
+type Options struct {
+	// Lookuper retrieves values of variables. OsLookuper is used if nil.
+	Lookuper Lookuper
+	// Prefix is prepended to keys of all variables,
+	// e.g. `env:"PORT"` with prefix "MYAPP_" is read from MYAPP_PORT.
+	Prefix string
+	// Strict makes Unmarshal fail when variables with the prefix are set
+	// but not used by any of the struct fields. It requires a non-empty prefix
+	// and a lookuper that implements Lister.
+	Strict bool
+	// Expand enables expansion of ${VAR} and ${VAR:-fallback} references in values
+	// and defaults of all variables. The `expand` tag overrides it per field.
+	Expand bool
+	// ListSeparator separates elements of slices and key-value pairs of maps.
+	// DefaultListSeparator is used if empty. The `sep` tag overrides it per field.
+	ListSeparator string
+	// KeyValueSeparator separates keys from values of maps.
+	// DefaultKeyValueSeparator is used if empty. The `kvsep` tag overrides it per field.
+	KeyValueSeparator string
+}
+
+func (o Options) lookuper() Lookuper {
+	if o.Lookuper == nil {
+		return OsLookuper{}
+	}
+	return o.Lookuper
+}
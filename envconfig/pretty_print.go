@@ -2,31 +2,144 @@ package envconfig
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"text/tabwriter"
 )
 
+// Column is a column of the table printed by Fprint.
+type Column int
+
+const (
+	// ColumnKey is a name of the env variable.
+	ColumnKey Column = iota
+	// ColumnField is a name of the struct field.
+	ColumnField
+	// ColumnType is a type of the struct field.
+	ColumnType
+	// ColumnValue is a value of the env variable. Sensitive values are redacted.
+	ColumnValue
+	// ColumnSource is a name of the source that supplied the value.
+	ColumnSource
+	// ColumnDefault is a value of the `default` tag.
+	ColumnDefault
+	// ColumnStatus is "v" if the field was unmarshaled successfully, "not set" if the optional
+	// variable is not set or an error otherwise followed by a warning if any.
+	ColumnStatus
+	// ColumnRawValue is a value of the env variable before expansion of references
+	// to other variables. Sensitive values are redacted.
+	ColumnRawValue
+)
+
+// DefaultColumns are columns printed by PrettyPrint.
+var DefaultColumns = []Column{ColumnKey, ColumnType, ColumnSource, ColumnStatus}
+
+var columnHeaders = map[Column]string{
+	ColumnKey:      "Env Variable",
+	ColumnField:    "Field",
+	ColumnType:     "Type",
+	ColumnValue:    "Value",
+	ColumnSource:   "Source",
+	ColumnDefault:  "Default",
+	ColumnStatus:   "OK",
+	ColumnRawValue: "Raw Value",
+}
+
+// PrintOptions configures output of Fprint.
+type PrintOptions struct {
+	// Columns to print. DefaultColumns are printed if empty.
+	Columns []Column
+	// HideOK hides fields that were unmarshaled successfully.
+	HideOK bool
+}
+
 // PrettyPrint prints verbose information about env variables specification, current values and
 // whether it was parsed correctly.
 func (f FieldUnmarshalResults) PrettyPrint() {
-	w := new(tabwriter.Writer)
+	_ = f.Fprint(output, PrintOptions{})
+}
+
+// Fprint writes verbose information about env variables specification, current values and
+// whether it was parsed correctly to w.
+func (f FieldUnmarshalResults) Fprint(w io.Writer, opts PrintOptions) error {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+
+	tw := new(tabwriter.Writer)
 	// minwidth, tabwidth, padding, padchar, flags
-	w.Init(output, 8, 8, 4, ' ', 0)
-	defer w.Flush()
+	tw.Init(w, 8, 8, 4, ' ', 0)
 
-	write := func(field, env, ok string) {
-		fmt.Fprintf(w, " %s\t%s\t%s\t\n", field, env, ok)
+	write := func(cells []string) {
+		fmt.Fprintf(tw, " %s\t\n", strings.Join(cells, "\t"))
 	}
 
-	write("Env Variable", "Type", "OK")
-	write("----", "----", "----")
+	headers := make([]string, len(columns))
+	separators := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = columnHeaders[column]
+		separators[i] = "----"
+	}
+	write(headers)
+	write(separators)
 
 	for _, result := range f {
-		var err string
-		if result.Err != nil {
-			err = result.Err.Error()
-		} else {
-			err = "v"
+		if opts.HideOK && result.Err == nil {
+			continue
 		}
-		write(result.KeyName, result.TypeName, err)
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = result.cell(column)
+		}
+		write(cells)
+	}
+
+	return tw.Flush()
+}
+
+func (r FieldUnmarshalResult) cell(column Column) string {
+	switch column {
+	case ColumnKey:
+		return r.KeyName
+	case ColumnField:
+		return r.FieldName
+	case ColumnType:
+		return r.TypeName
+	case ColumnValue:
+		return r.Value
+	case ColumnSource:
+		return r.source()
+	case ColumnDefault:
+		return r.Default
+	case ColumnStatus:
+		return r.status()
+	case ColumnRawValue:
+		return r.RawValue
+	}
+	return ""
+}
+
+// source describes where the value was found.
+func (r FieldUnmarshalResult) source() string {
+	if r.File != "" {
+		return fmt.Sprintf("file %s (%s)", r.File, r.Source)
+	}
+	return r.Source
+}
+
+// status is "v" if the field was unmarshaled successfully, "not set" if the optional
+// variable is not set or an error otherwise. A warning is appended if any.
+func (r FieldUnmarshalResult) status() string {
+	status := "v"
+	switch {
+	case r.Err != nil:
+		status = r.Err.Error()
+	case r.NotSet:
+		status = "not set"
+	}
+	if r.Warning != "" {
+		status = fmt.Sprintf("%s (warning: %s)", status, r.Warning)
 	}
+	return status
 }
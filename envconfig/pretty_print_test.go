@@ -47,26 +47,64 @@ func TestFieldParseResults_PrettyPrint(t *testing.T) {
 	}()
 	results.PrettyPrint()
 
-	expected := ` Env Variable                 Type                   OK                                                                                                                            
- ----                         ----                   ----                                                                                                                          
- ENV_CONFIG_ADMIN_USERS       []string               v                                                                                                                             
- ENV_CONFIG_BYTE_SLICE        []uint8                v                                                                                                                             
- ENV_CONFIG_COLOR_CODES       map[string]int         v                                                                                                                             
- ENV_CONFIG_DEBUG             bool                   v                                                                                                                             
- ENV_CONFIG_EMBEDDED_PORT     int                    assigning ENV_CONFIG_EMBEDDED_PORT="invalid" to EmbeddedPort type int: strconv.ParseInt: parsing "invalid": invalid syntax    
- ENV_CONFIG_EMPTY_NUMBERS     []int                  v                                                                                                                             
- ENV_CONFIG_ENABLED           bool                   v                                                                                                                             
- ENV_CONFIG_MAGIC_NUMBERS     []int                  v                                                                                                                             
- ENV_CONFIG_MULTI_WORD_VAR    string                 v                                                                                                                             
- ENV_CONFIG_PORT              int                    v                                                                                                                             
- ENV_CONFIG_RATE              float32                v                                                                                                                             
- ENV_CONFIG_TIMEOUT           time.Duration          v                                                                                                                             
- ENV_CONFIG_TTL               uint32                 v                                                                                                                             
- ENV_CONFIG_URL_POINTER       envconfig.CustomURL    v                                                                                                                             
- ENV_CONFIG_URL_VALUE         envconfig.CustomURL    v                                                                                                                             
- ENV_CONFIG_USER              string                 v                                                                                                                             
+	expected := ` Env Variable                 Type                   Source     OK                                                                                                                            
+ ----                         ----                   ----       ----                                                                                                                          
+ ENV_CONFIG_ADMIN_USERS       []string               default    v                                                                                                                             
+ ENV_CONFIG_BYTE_SLICE        []uint8                default    v                                                                                                                             
+ ENV_CONFIG_COLOR_CODES       map[string]int         default    v                                                                                                                             
+ ENV_CONFIG_DEBUG             bool                   env        v                                                                                                                             
+ ENV_CONFIG_EMBEDDED_PORT     int                    env        assigning ENV_CONFIG_EMBEDDED_PORT="invalid" to EmbeddedPort type int: strconv.ParseInt: parsing "invalid": invalid syntax    
+ ENV_CONFIG_EMPTY_NUMBERS     []int                  default    v                                                                                                                             
+ ENV_CONFIG_ENABLED           bool                   default    v                                                                                                                             
+ ENV_CONFIG_MAGIC_NUMBERS     []int                  default    v                                                                                                                             
+ ENV_CONFIG_MULTI_WORD_VAR    string                 default    v                                                                                                                             
+ ENV_CONFIG_PORT              int                    default    v                                                                                                                             
+ ENV_CONFIG_RATE              float32                default    v                                                                                                                             
+ ENV_CONFIG_TIMEOUT           time.Duration          default    v                                                                                                                             
+ ENV_CONFIG_TTL               uint32                 default    v                                                                                                                             
+ ENV_CONFIG_URL_POINTER       envconfig.CustomURL    default    v                                                                                                                             
+ ENV_CONFIG_URL_VALUE         envconfig.CustomURL    default    v                                                                                                                             
+ ENV_CONFIG_USER              string                 default    v                                                                                                                             
 `
 
 	ioutil.WriteFile("/tmp/foo", buf.Bytes(), 777)
 	require.Equal(t, expected, buf.String())
 }
+
+func TestFieldParseResults_Fprint(t *testing.T) {
+	var s struct {
+		Debug    bool   `env:"ENV_CONFIG_DEBUG" default:"false"`
+		Port     int    `env:"ENV_CONFIG_PORT"`
+		Password string `env:"ENV_CONFIG_PASSWORD" default:"changeme" sensitive:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_DEBUG":    "true",
+		"ENV_CONFIG_PASSWORD": "s3cr3t",
+	})
+	require.Error(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	err = results.Fprint(buf, PrintOptions{
+		Columns: []Column{ColumnKey, ColumnField, ColumnValue, ColumnDefault, ColumnSource, ColumnStatus},
+	})
+	require.NoError(t, err)
+
+	expected := ` Env Variable           Field       Value     Default    Source    OK                                            
+ ----                   ----        ----      ----       ----      ----                                          
+ ENV_CONFIG_DEBUG       Debug       true      false      map       v                                             
+ ENV_CONFIG_PASSWORD    Password    ******    ******     map       v                                             
+ ENV_CONFIG_PORT        Port                                       env variable is not set: "ENV_CONFIG_PORT"    
+`
+	require.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	err = results.Fprint(buf, PrintOptions{HideOK: true})
+	require.NoError(t, err)
+
+	expected = ` Env Variable       Type    Source    OK                                            
+ ----               ----    ----      ----                                          
+ ENV_CONFIG_PORT    int               env variable is not set: "ENV_CONFIG_PORT"    
+`
+	require.Equal(t, expected, buf.String())
+}
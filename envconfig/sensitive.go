@@ -0,0 +1,44 @@
+package envconfig
+
+import "strconv"
+
+const (
+	// maskedValue replaces values of sensitive fields.
+	maskedValue = "******"
+	// sensitiveLast4 is a value of the `sensitive` tag which keeps last 4 characters visible.
+	sensitiveLast4 = "last4"
+)
+
+// maskValue redacts the value according to the value of the `sensitive` tag.
+// "true" hides the value completely, "last4" keeps last 4 characters visible
+// and "false" or empty tag leaves the value as is.
+func maskValue(value, sensitive string) string {
+	if sensitive == "" {
+		return value
+	}
+	if sensitive == sensitiveLast4 {
+		runes := []rune(value)
+		if len(runes) <= 4 {
+			return maskedValue
+		}
+		return maskedValue + string(runes[len(runes)-4:])
+	}
+	if hide, err := strconv.ParseBool(sensitive); err == nil && !hide {
+		return value
+	}
+	return maskedValue
+}
+
+// redactedError hides the message of the underlying error, which may contain the sensitive value
+// or any part of it, e.g. an element of a list. The underlying error is available with errors.Unwrap.
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return "invalid value, details are redacted"
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,130 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value, sensitive, expected string
+	}{
+		{value: "secret", sensitive: "", expected: "secret"},
+		{value: "secret", sensitive: "false", expected: "secret"},
+		{value: "secret", sensitive: "true", expected: "******"},
+		{value: "secret", sensitive: "yes", expected: "******"},
+		{value: "", sensitive: "true", expected: "******"},
+		{value: "4111111111111111", sensitive: "last4", expected: "******1111"},
+		{value: "1111", sensitive: "last4", expected: "******"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, maskValue(test.value, test.sensitive), test)
+	}
+}
+
+func TestSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Password string `env:"ENV_CONFIG_PASSWORD" sensitive:"true"`
+		Card     string `env:"ENV_CONFIG_CARD" sensitive:"last4"`
+		Pin      int    `env:"ENV_CONFIG_PIN" sensitive:"true"`
+		User     string `env:"ENV_CONFIG_USER"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PASSWORD": "s3cr3t",
+		"ENV_CONFIG_CARD":     "4111111111111111",
+		"ENV_CONFIG_PIN":      "12a4",
+		"ENV_CONFIG_USER":     "Kelsey",
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, "s3cr3t", s.Password)
+	assert.Equal(t, "4111111111111111", s.Card)
+
+	values := make(map[string]string, len(results))
+	for _, result := range results {
+		values[result.KeyName] = result.Value
+		assert.Equal(t, result.KeyName != "ENV_CONFIG_USER", result.Sensitive)
+	}
+	assert.Equal(t, map[string]string{
+		"ENV_CONFIG_PASSWORD": "******",
+		"ENV_CONFIG_CARD":     "******1111",
+		"ENV_CONFIG_PIN":      "******",
+		"ENV_CONFIG_USER":     "Kelsey",
+	}, values)
+
+	assert.NotContains(t, err.Error(), "12a4")
+	assert.Equal(t, `assigning ENV_CONFIG_PIN="******" to Pin type int: invalid value, details are redacted`, err.Error())
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "******", parseErr.Value)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+}
+
+func TestSensitiveListsAndMaps(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Pins    []int          `env:"PINS" sensitive:"true"`
+		Tokens  map[string]int `env:"TOKENS" sensitive:"true"`
+		Entries map[string]int `env:"ENTRIES" sensitive:"last4"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"PINS":    "1,topsecret",
+		"TOKENS":  "a:1,b:hunter2",
+		"ENTRIES": "a:1,hunter2,b:2",
+	})
+	require.Error(t, err)
+
+	for _, secret := range []string{"topsecret", "hunter2"} {
+		assert.NotContains(t, err.Error(), secret)
+	}
+	assert.Equal(t, `assigning ENTRIES="******,b:2" to Entries type map[string]int: invalid value, details are redacted`+"\n"+
+		`assigning PINS="******" to Pins type []int: invalid value, details are redacted`+"\n"+
+		`assigning TOKENS="******" to Tokens type map[string]int: invalid value, details are redacted`, err.Error())
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+}
+
+func TestSensitiveNotSet(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Password string `env:"PASSWORD" sensitive:"true"`
+		Token    string `env:"TOKEN" sensitive:"true" optional:"true"`
+		Key      string `env:"KEY" sensitive:"true" default:"s3cr3t"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{})
+	require.Error(t, err)
+
+	values := make(map[string]string, len(results))
+	for _, result := range results {
+		values[result.KeyName] = result.Value
+	}
+	assert.Equal(t, map[string]string{"KEY": "******", "PASSWORD": "", "TOKEN": ""}, values)
+}
+
+func TestSensitiveUsage(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Key string `env:"KEY" sensitive:"true" default:"s3cr3t"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := Usagef(&s, buf, "{{range .}}{{usage_key .}}|{{usage_default .}}\n{{end}}")
+	require.NoError(t, err)
+	assert.Equal(t, "KEY|******\n", buf.String())
+}
@@ -0,0 +1,49 @@
+package envconfig
+
+import "encoding/json"
+
+// jsonResult is a JSON representation of a FieldUnmarshalResult.
+type jsonResult struct {
+	Key       string `json:"key"`
+	Field     string `json:"field"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	RawValue  string `json:"raw_value,omitempty"`
+	Sensitive bool   `json:"sensitive,omitempty"`
+	Source    string `json:"source,omitempty"`
+	File      string `json:"file,omitempty"`
+	Default   string `json:"default,omitempty"`
+	Warning   string `json:"warning,omitempty"`
+	NotSet    bool   `json:"not_set,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (r FieldUnmarshalResult) toJSON() jsonResult {
+	result := jsonResult{
+		Key:       r.KeyName,
+		Field:     r.FieldName,
+		Type:      r.TypeName,
+		Value:     r.Value,
+		RawValue:  r.RawValue,
+		Sensitive: r.Sensitive,
+		Source:    r.Source,
+		File:      r.File,
+		Default:   r.Default,
+		Warning:   r.Warning,
+		NotSet:    r.NotSet,
+	}
+	if r.Err != nil {
+		result.Error = r.Err.Error()
+	}
+	return result
+}
+
+// MarshalJSON implements json.Marshaler. Results are encoded as an array
+// with an object per env variable. Values of sensitive fields are redacted.
+func (f FieldUnmarshalResults) MarshalJSON() ([]byte, error) {
+	results := make([]jsonResult, len(f))
+	for i, result := range f {
+		results[i] = result.toJSON()
+	}
+	return json.Marshal(results)
+}
@@ -0,0 +1,38 @@
+package envconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldUnmarshalResults_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Debug    bool   `env:"ENV_CONFIG_DEBUG" default:"false"`
+		Port     int    `env:"ENV_CONFIG_PORT"`
+		Password string `env:"ENV_CONFIG_PASSWORD" sensitive:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PORT":     "invalid",
+		"ENV_CONFIG_PASSWORD": "s3cr3t",
+	})
+	require.Error(t, err)
+
+	data, err := json.Marshal(results)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `[
+		{"key": "ENV_CONFIG_DEBUG", "field": "Debug", "type": "bool", "value": "false", "source": "default", "default": "false"},
+		{"key": "ENV_CONFIG_PASSWORD", "field": "Password", "type": "string", "value": "******", "sensitive": true, "source": "map"},
+		{"key": "ENV_CONFIG_PORT", "field": "Port", "type": "int", "value": "invalid", "source": "map",
+		 "error": "assigning ENV_CONFIG_PORT=\"invalid\" to Port type int: strconv.ParseInt: parsing \"invalid\": invalid syntax"}
+	]`, string(data))
+
+	data, err = json.Marshal(FieldUnmarshalResults(nil))
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(data))
+}
@@ -15,30 +15,84 @@ var ErrInvalidSpecification = errors.New("specification must be a struct pointer
 
 // Unmarshal populates the specified struct based on environment variables and
 // returns a slice with result of parsing each struct's field.
+// If any of the fields fails, the returned error is a *MultiError listing all of them.
 func Unmarshal(spec interface{}) (FieldUnmarshalResults, error) {
-	infos, err := gatherInfo(spec)
+	return UnmarshalFrom(spec, OsLookuper{})
+}
+
+// UnmarshalFrom populates the specified struct based on variables retrieved
+// from the specified lookuper and returns a slice with result of parsing
+// each struct's field.
+func UnmarshalFrom(spec interface{}, l Lookuper) (FieldUnmarshalResults, error) {
+	return UnmarshalWithOptions(spec, Options{Lookuper: l})
+}
+
+// UnmarshalWithOptions populates the specified struct based on variables retrieved
+// according to the specified options and returns a slice with result of parsing
+// each struct's field.
+func UnmarshalWithOptions(spec interface{}, opts Options) (FieldUnmarshalResults, error) {
+	l := opts.lookuper()
+
+	infos, err := gatherInfo(spec, opts.Prefix, l)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := gatherGroups(infos)
 	if err != nil {
 		return nil, err
 	}
 
-	collisionIndex := make(map[string]int, len(infos))
+	collisionIndex := make(map[string][]string, len(infos))
 	for _, info := range infos {
-		collisionIndex[info.Key]++
+		collisionIndex[info.Key] = append(collisionIndex[info.Key], info.Name)
+	}
+
+	lookups := make([]lookupResult, len(infos))
+	errs := make([]error, len(infos))
+	for i, info := range infos {
+		if fields := collisionIndex[info.Key]; len(fields) > 1 {
+			errs[i] = &DuplicateKeyError{Key: info.Key, Fields: fields}
+			continue
+		}
+		lookups[i], errs[i] = info.LookupValue(l)
+	}
+
+	newExpander(infos, lookups, errs, l, opts.Expand).ExpandAll()
+
+	// conditions are evaluated when values of all variables are known
+	values := resolvedValues{
+		lookups: make(map[string]lookupResult, len(infos)),
+		types:   make(map[string]reflect.Type, len(infos)),
+		l:       l,
+	}
+	for i, info := range infos {
+		values.lookups[info.Key] = lookups[i]
+		values.types[info.Key] = info.Field.Type()
+	}
+	for i, info := range infos {
+		if errs[i] == nil {
+			errs[i] = info.Conditions.Check(info.Key, lookups[i], values)
+		}
 	}
 
 	results := make(FieldUnmarshalResults, len(infos))
 	for i, info := range infos {
-		var (
-			err   error
-			value string
-		)
-		if collisionIndex[info.Key] > 1 {
-			err = fmt.Errorf("duplicate env variable name %[1]s for %[2]s", info.Key, info.Name)
-		} else {
-			value, err = processInfo(infos[i])
-			if err != nil {
-				err = fmt.Errorf("assigning %[1]s=%[3]q to %[2]s type %[4]s: %[5]w", info.Key, info.Name, value, info.Field.Type().String(), err)
-			}
+		lookup, err := lookups[i], errs[i]
+		if err == nil && !lookup.NotSet {
+			err = processInfo(info, lookup.Value, info.ValueFormat(opts))
+		}
+		if err == nil && info.Store != nil {
+			info.Store()
+		}
+
+		// a redacted value of a variable that is not set would look as if it was supplied
+		var value, rawValue string
+		if lookup.Source != "" {
+			value = info.MaskValue(lookup.Value)
+		}
+		if lookup.RawValue != "" {
+			rawValue = info.MaskValue(lookup.RawValue)
 		}
 
 		results[i] = FieldUnmarshalResult{
@@ -46,23 +100,58 @@ func Unmarshal(spec interface{}) (FieldUnmarshalResults, error) {
 			FieldName: info.Name,
 			TypeName:  info.Field.Type().String(),
 			Value:     value,
+			RawValue:  rawValue,
+			Sensitive: info.IsSensitive(),
+			Source:    lookup.Source,
+			File:      lookup.File,
+			Default:   info.MaskedDefault(),
+			Warning:   lookup.Warning,
+			NotSet:    lookup.NotSet,
 			Err:       err,
 		}
 	}
 
-	return results, results.firstError()
+	results = append(results, groupResults(groups, values)...)
+
+	// cross-field rules are meaningless until all fields are populated
+	if len(results.Errors()) == 0 {
+		results = append(results, validatorResults(spec)...)
+	}
+
+	if opts.Strict {
+		unknown, err := unknownResults(opts.Prefix, l, infos)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, unknown...)
+	}
+
+	return results, results.err()
 }
 
-func processInfo(info envVarInfo) (string, error) {
-	value, err := info.GetValueFromEnv()
-	if err != nil {
-		return "", fmt.Errorf("get env value: %w", err)
+// processInfo unmarshals the value of the variable in the specified format
+// into the struct field and validates it.
+func processInfo(info envVarInfo, value string, format valueFormat) error {
+	if info.IsNotEmpty && strings.TrimSpace(value) == "" {
+		return &EmptyValueError{Key: info.Key}
 	}
 
-	return value, unmarshalFieldValue(value, info.Field)
+	if err := unmarshalFieldValue(value, info.Field, format); err != nil {
+		if info.IsSensitive() {
+			err = &redactedError{err: err}
+		}
+		return &ParseError{
+			Key:   info.Key,
+			Field: info.Name,
+			Type:  info.Field.Type().String(),
+			Value: info.MaskValue(value),
+			Err:   err,
+		}
+	}
+	return info.Validate(value)
 }
 
-func unmarshalFieldValue(value string, field reflect.Value) error {
+func unmarshalFieldValue(value string, field reflect.Value, format valueFormat) error {
 	typ := field.Type()
 
 	decoder := decoderFrom(field)
@@ -134,10 +223,13 @@ func unmarshalFieldValue(value string, field reflect.Value) error {
 		if typ.Elem().Kind() == reflect.Uint8 {
 			sl = reflect.ValueOf([]byte(value))
 		} else if len(strings.TrimSpace(value)) != 0 {
-			vals := strings.Split(value, ",")
+			vals, err := format.split(value, format.Sep)
+			if err != nil {
+				return err
+			}
 			sl = reflect.MakeSlice(typ, len(vals), len(vals))
 			for i, val := range vals {
-				err := unmarshalFieldValue(val, sl.Index(i))
+				err := unmarshalFieldValue(val.Value, sl.Index(i), format)
 				if err != nil {
 					return err
 				}
@@ -147,19 +239,34 @@ func unmarshalFieldValue(value string, field reflect.Value) error {
 	case reflect.Map:
 		mp := reflect.MakeMap(typ)
 		if len(strings.TrimSpace(value)) != 0 {
-			pairs := strings.Split(value, ",")
-			for _, pair := range pairs {
-				kvpair := strings.Split(pair, ":")
-				if len(kvpair) != 2 {
-					return fmt.Errorf("invalid map item: %q", pair)
+			items, err := format.split(value, format.Sep, format.KVSep)
+			if err != nil {
+				return err
+			}
+			for len(items) > 0 {
+				// a pair ends with the list separator or at the end of the value
+				n := 1
+				for n < len(items) && items[n-1].Sep == format.KVSep {
+					n++
+				}
+				pair := items[:n]
+				items = items[n:]
+				if len(pair) < 2 {
+					return fmt.Errorf("invalid map item: %q", pair[0].Raw)
 				}
 				k := reflect.New(typ.Key()).Elem()
-				err := unmarshalFieldValue(kvpair[0], k)
+				err := unmarshalFieldValue(pair[0].Value, k, format)
 				if err != nil {
 					return err
 				}
+				// the value is everything after the first key-value separator
+				vals := make([]string, len(pair)-1)
+				for i, it := range pair[1:] {
+					vals[i] = it.Value
+				}
+				val := strings.Join(vals, format.KVSep)
 				v := reflect.New(typ.Elem()).Elem()
-				err = unmarshalFieldValue(kvpair[1], v)
+				err = unmarshalFieldValue(val, v, format)
 				if err != nil {
 					return err
 				}
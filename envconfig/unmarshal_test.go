@@ -4,8 +4,10 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -402,6 +404,243 @@ func TestEmbeddedStruct(t *testing.T) {
 	assert.Equal(t, "foobaz", s.String)
 }
 
+func TestFileSuffix(t *testing.T) {
+	f, err := ioutil.TempFile("", "envconfig")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("s3cr3t\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	var s struct {
+		Password string `env:"ENV_CONFIG_PASSWORD" file:"true"`
+		User     string `env:"ENV_CONFIG_USER" file:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PASSWORD_FILE": f.Name(),
+		"ENV_CONFIG_USER":          "Kelsey",
+		"ENV_CONFIG_USER_FILE":     f.Name(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "s3cr3t", s.Password)
+	assert.Equal(t, "Kelsey", s.User)
+
+	assert.Equal(t, "ENV_CONFIG_PASSWORD", results[0].KeyName)
+	assert.Equal(t, "map", results[0].Source)
+	assert.Equal(t, f.Name(), results[0].File)
+	assert.Equal(t, "", results[1].File)
+}
+
+func TestFileSuffixMissingFile(t *testing.T) {
+	var s struct {
+		Password string `env:"ENV_CONFIG_PASSWORD" file:"true"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PASSWORD_FILE": "testdata/does-not-exist",
+	})
+	var pathErr *os.PathError
+	require.True(t, errors.As(err, &pathErr))
+	require.Equal(t, "testdata/does-not-exist", pathErr.Path)
+	require.Contains(t, err.Error(), "ENV_CONFIG_PASSWORD_FILE")
+}
+
+func TestFileSuffixDisabled(t *testing.T) {
+	var s struct {
+		Password string `env:"ENV_CONFIG_PASSWORD"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PASSWORD_FILE": "testdata/test.env",
+	})
+	require.Error(t, err)
+}
+
+func TestEnvPrefix(t *testing.T) {
+	type Credentials struct {
+		User     string `env:"USER"`
+		Password string `env:"PASSWORD" default:"changeme"`
+	}
+	type DBConfig struct {
+		Host        string `env:"HOST"`
+		Port        int    `env:"PORT" default:"5432"`
+		Credentials `envPrefix:"CREDENTIALS_"`
+	}
+
+	var s struct {
+		Primary DBConfig  `envPrefix:"ENV_CONFIG_PRIMARY_"`
+		Replica *DBConfig `envPrefix:"ENV_CONFIG_REPLICA_"`
+		Debug   bool      `env:"ENV_CONFIG_DEBUG"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PRIMARY_HOST":             "primary",
+		"ENV_CONFIG_PRIMARY_CREDENTIALS_USER": "admin",
+		"ENV_CONFIG_REPLICA_HOST":             "replica",
+		"ENV_CONFIG_REPLICA_PORT":             "5433",
+		"ENV_CONFIG_REPLICA_CREDENTIALS_USER": "reader",
+		"ENV_CONFIG_DEBUG":                    "true",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, DBConfig{Host: "primary", Port: 5432, Credentials: Credentials{User: "admin", Password: "changeme"}}, s.Primary)
+	assert.Equal(t, &DBConfig{Host: "replica", Port: 5433, Credentials: Credentials{User: "reader", Password: "changeme"}}, s.Replica)
+	assert.True(t, s.Debug)
+
+	keys := make([]string, len(results))
+	for i, result := range results {
+		keys[i] = result.KeyName
+	}
+	assert.Equal(t, []string{
+		"ENV_CONFIG_DEBUG",
+		"ENV_CONFIG_PRIMARY_CREDENTIALS_PASSWORD",
+		"ENV_CONFIG_PRIMARY_CREDENTIALS_USER",
+		"ENV_CONFIG_PRIMARY_HOST",
+		"ENV_CONFIG_PRIMARY_PORT",
+		"ENV_CONFIG_REPLICA_CREDENTIALS_PASSWORD",
+		"ENV_CONFIG_REPLICA_CREDENTIALS_USER",
+		"ENV_CONFIG_REPLICA_HOST",
+		"ENV_CONFIG_REPLICA_PORT",
+	}, keys)
+}
+
+func TestEnvPrefixOnNonStructField(t *testing.T) {
+	var s struct {
+		Debug bool `env:"DEBUG" envPrefix:"ENV_CONFIG_"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{})
+	require.True(t, errors.Is(err, ErrInvalidSpecification))
+}
+
+func TestUnmarshalWithPrefix(t *testing.T) {
+	type Specification struct {
+		Port     int `env:"PORT"`
+		Database struct {
+			Host string `env:"HOST"`
+		} `envPrefix:"DB_"`
+	}
+
+	l := MapLookuper{
+		"FIRST_PORT":     "8080",
+		"FIRST_DB_HOST":  "first",
+		"SECOND_PORT":    "9090",
+		"SECOND_DB_HOST": "second",
+	}
+
+	var first, second Specification
+	results, err := UnmarshalWithOptions(&first, Options{Lookuper: l, Prefix: "FIRST_"})
+	require.NoError(t, err)
+	_, err = UnmarshalWithOptions(&second, Options{Lookuper: l, Prefix: "SECOND_"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, first.Port)
+	assert.Equal(t, "first", first.Database.Host)
+	assert.Equal(t, 9090, second.Port)
+	assert.Equal(t, "second", second.Database.Host)
+
+	assert.Equal(t, "FIRST_DB_HOST", results[0].KeyName)
+	assert.Equal(t, "FIRST_PORT", results[1].KeyName)
+}
+
+func TestAliases(t *testing.T) {
+	var s struct {
+		URL  string `env:"DB_URL" aliases:"DATABASE_URL, LEGACY_DATABASE_URL"`
+		Host string `env:"HOST" aliases:"HOSTNAME"`
+		Port int    `env:"PORT" aliases:"LISTEN_PORT" default:"8080"`
+	}
+
+	results, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"ENV_CONFIG_DATABASE_URL":        "postgres://new",
+			"ENV_CONFIG_LEGACY_DATABASE_URL": "postgres://legacy",
+			"ENV_CONFIG_HOST":                "canonical",
+			"ENV_CONFIG_HOSTNAME":            "deprecated",
+		},
+		Prefix: "ENV_CONFIG_",
+		Strict: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://new", s.URL)
+	assert.Equal(t, "canonical", s.Host)
+	assert.Equal(t, 8080, s.Port)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, "ENV_CONFIG_DB_URL", results[0].KeyName)
+	assert.Equal(t, "ENV_CONFIG_DATABASE_URL is deprecated, use ENV_CONFIG_DB_URL", results[0].Warning)
+	assert.Equal(t, "", results[1].Warning)
+	assert.Equal(t, "", results[2].Warning)
+	assert.Equal(t, "v (warning: ENV_CONFIG_DATABASE_URL is deprecated, use ENV_CONFIG_DB_URL)", results[0].status())
+}
+
+func TestOptional(t *testing.T) {
+	var s struct {
+		Timeout   *time.Duration `env:"ENV_CONFIG_TIMEOUT" optional:"true"`
+		User      string         `env:"ENV_CONFIG_USER" optional:"true"`
+		Port      int            `env:"ENV_CONFIG_PORT" optional:"true" default:"8080"`
+		Rate      *float64       `env:"ENV_CONFIG_RATE" optional:"true"`
+		Invalid   int            `env:"ENV_CONFIG_INVALID" optional:"true"`
+		Unchanged string         `env:"ENV_CONFIG_UNCHANGED" optional:"true"`
+	}
+	s.Unchanged = "initial"
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_RATE":    "0.5",
+		"ENV_CONFIG_INVALID": "invalid",
+	})
+	require.Error(t, err)
+
+	assert.Nil(t, s.Timeout)
+	assert.Equal(t, "", s.User)
+	assert.Equal(t, 8080, s.Port)
+	require.NotNil(t, s.Rate)
+	assert.Equal(t, 0.5, *s.Rate)
+	assert.Equal(t, "initial", s.Unchanged)
+
+	notSet := make(map[string]bool, len(results))
+	for _, result := range results {
+		notSet[result.KeyName] = result.NotSet
+	}
+	assert.Equal(t, map[string]bool{
+		"ENV_CONFIG_TIMEOUT":   true,
+		"ENV_CONFIG_USER":      true,
+		"ENV_CONFIG_PORT":      false,
+		"ENV_CONFIG_RATE":      false,
+		"ENV_CONFIG_INVALID":   false,
+		"ENV_CONFIG_UNCHANGED": true,
+	}, notSet)
+	assert.Equal(t, "not set", results[3].status())
+	assert.Len(t, results.Errors(), 1)
+}
+
+func TestNotEmpty(t *testing.T) {
+	var s struct {
+		Key      string `env:"ENV_CONFIG_KEY" notempty:"true"`
+		Blank    string `env:"ENV_CONFIG_BLANK" notempty:"true"`
+		Default  string `env:"ENV_CONFIG_DEFAULT" notempty:"true" default:""`
+		Optional string `env:"ENV_CONFIG_OPTIONAL" notempty:"true" optional:"true"`
+		Empty    string `env:"ENV_CONFIG_EMPTY"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_KEY":   "secret",
+		"ENV_CONFIG_BLANK": " \t",
+		"ENV_CONFIG_EMPTY": "",
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, "secret", s.Key)
+	assert.Equal(t, "", s.Blank)
+
+	require.Len(t, results.Errors(), 2)
+	assert.Equal(t, &EmptyValueError{Key: "ENV_CONFIG_BLANK"}, results[0].Err)
+	assert.Equal(t, &EmptyValueError{Key: "ENV_CONFIG_DEFAULT"}, results[1].Err)
+	assert.Equal(t, `env variable is empty: "ENV_CONFIG_BLANK"`, results[0].Err.Error())
+}
+
 func TestNonPointerFailsProperly(t *testing.T) {
 	var s struct{}
 	os.Clearenv()
@@ -543,6 +782,83 @@ func TestBinaryUnmarshalerError(t *testing.T) {
 	}
 }
 
+func TestAggregatedErrors(t *testing.T) {
+	var s struct {
+		Datetime   time.Time  `env:"ENV_CONFIG_DATETIME"`
+		UrlPointer *CustomURL `env:"ENV_CONFIG_URL_POINTER"`
+		Port       int        `env:"ENV_CONFIG_PORT"`
+		User       string     `env:"ENV_CONFIG_USER"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_DATETIME":    "I'M NOT A DATE",
+		"ENV_CONFIG_URL_POINTER": "http://%41:8080/",
+		"ENV_CONFIG_USER":        "Kelsey",
+	})
+	require.Error(t, err)
+
+	var multiErr *MultiError
+	require.True(t, errors.As(err, &multiErr))
+	require.Len(t, multiErr.Errors, 3)
+	require.Equal(t, results.Errors(), multiErr.Errors)
+
+	var parseErr *time.ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	var urlErr *url.Error
+	assert.True(t, errors.As(err, &urlErr))
+
+	assert.Contains(t, err.Error(), "ENV_CONFIG_DATETIME")
+	assert.Contains(t, err.Error(), "ENV_CONFIG_URL_POINTER")
+	assert.Contains(t, err.Error(), "ENV_CONFIG_PORT")
+	assert.NotContains(t, err.Error(), "ENV_CONFIG_USER")
+	assert.Equal(t, "Kelsey", s.User)
+}
+
+func TestNoErrors(t *testing.T) {
+	var s struct {
+		User string `env:"ENV_CONFIG_USER"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{"ENV_CONFIG_USER": "Kelsey"})
+	require.NoError(t, err)
+	require.Empty(t, results.Errors())
+}
+
+func TestTypedErrors(t *testing.T) {
+	var s struct {
+		Port    int    `env:"ENV_CONFIG_PORT"`
+		User    string `env:"ENV_CONFIG_USER"`
+		A       string `env:"ENV_CONFIG_DUPLICATE"`
+		B       string `env:"ENV_CONFIG_DUPLICATE"`
+		Enabled bool   `env:"ENV_CONFIG_ENABLED" default:"true"`
+	}
+
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"ENV_CONFIG_PORT":      "invalid",
+		"ENV_CONFIG_DUPLICATE": "foo",
+	})
+	require.Error(t, err)
+
+	var dupErr *DuplicateKeyError
+	require.True(t, errors.As(results[0].Err, &dupErr))
+	assert.Equal(t, &DuplicateKeyError{Key: "ENV_CONFIG_DUPLICATE", Fields: []string{"A", "B"}}, dupErr)
+	assert.Equal(t, "duplicate env variable name ENV_CONFIG_DUPLICATE for A, B", dupErr.Error())
+
+	var parseErr *ParseError
+	require.True(t, errors.As(results[3].Err, &parseErr))
+	assert.Equal(t, "ENV_CONFIG_PORT", parseErr.Key)
+	assert.Equal(t, "Port", parseErr.Field)
+	assert.Equal(t, "int", parseErr.Type)
+	assert.Equal(t, "invalid", parseErr.Value)
+	assert.True(t, errors.Is(parseErr, strconv.ErrSyntax))
+
+	var missingErr *MissingVariableError
+	require.True(t, errors.As(results[4].Err, &missingErr))
+	assert.Equal(t, &MissingVariableError{Key: "ENV_CONFIG_USER"}, missingErr)
+
+	assert.NoError(t, results[2].Err)
+}
+
 func TestCheckUnknownEmptyPrefix(t *testing.T) {
 	var s struct{}
 	os.Clearenv()
@@ -591,6 +907,71 @@ func TestCheckDisallowedIgnored(t *testing.T) {
 	require.Equal(t, []string{"ENV_CONFIG_IGNORED"}, unknownVars)
 }
 
+func TestStrict(t *testing.T) {
+	var s struct {
+		Port     int    `env:"PORT" default:"8080"`
+		Debug    bool   `env:"DEBUG" default:"false"`
+		Password string `env:"PASSWORD" file:"true"`
+	}
+
+	results, err := UnmarshalWithOptions(&s, Options{
+		Lookuper: MapLookuper{
+			"ENV_CONFIG_PROT":          "9090",
+			"ENV_CONFIG_PASSWORD_FILE": "testdata/test.env",
+			"ENV_CONFIG_UNRELATED":     "true",
+			"OTHER_VAR":                "true",
+		},
+		Prefix: "ENV_CONFIG_",
+		Strict: true,
+	})
+	require.Error(t, err)
+	assert.Equal(t, 8080, s.Port)
+
+	require.Len(t, results, 5)
+	assert.Equal(t, "ENV_CONFIG_PROT", results[3].KeyName)
+	assert.Equal(t, &UnknownVariableError{Key: "ENV_CONFIG_PROT", Suggestion: "ENV_CONFIG_PORT"}, results[3].Err)
+	assert.Equal(t, "ENV_CONFIG_UNRELATED", results[4].KeyName)
+	assert.Equal(t, &UnknownVariableError{Key: "ENV_CONFIG_UNRELATED"}, results[4].Err)
+
+	assert.Equal(t, "unknown env variable is set: \"ENV_CONFIG_PROT\"; did you mean \"ENV_CONFIG_PORT\"?\n"+
+		"unknown env variable is set: \"ENV_CONFIG_UNRELATED\"", err.Error())
+}
+
+func TestStrictEmptyPrefix(t *testing.T) {
+	var s struct {
+		Port int `env:"PORT" default:"8080"`
+	}
+
+	_, err := UnmarshalWithOptions(&s, Options{Lookuper: MapLookuper{}, Strict: true})
+	require.True(t, errors.Is(err, ErrEmptyPrefix))
+}
+
+func TestStrictWrappedLookuper(t *testing.T) {
+	var s struct {
+		Port int `env:"PORT" default:"8080"`
+	}
+
+	l := lookupFunc(func(key string) (string, bool) {
+		if key == "ENV_CONFIG_PROT" {
+			return "9090", true
+		}
+		return "", false
+	})
+	for _, wrapped := range []Lookuper{ChainLookuper(MapLookuper{}, l), NamedLookuper("cli", l)} {
+		_, err := UnmarshalWithOptions(&s, Options{Lookuper: wrapped, Prefix: "ENV_CONFIG_", Strict: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must implement Lister")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("PORT", "PORT"))
+	assert.Equal(t, 1, editDistance("PROT", "PORT"))
+	assert.Equal(t, 1, editDistance("PORTS", "PORT"))
+	assert.Equal(t, 2, editDistance("HOST", "PORT"))
+	assert.Equal(t, 4, editDistance("", "PORT"))
+}
+
 func TestErrorMessageForRequired(t *testing.T) {
 	var s struct {
 		Foo string `env:"BAR" `
@@ -652,6 +1033,6 @@ func BenchmarkGatherInfo(b *testing.B) {
 	os.Setenv("ENV_CONFIG_MULTI_WORD_VAR_WITH_AUTO_SPLIT", "24")
 	for i := 0; i < b.N; i++ {
 		var s Specification
-		gatherInfo(&s)
+		gatherInfo(&s, "", OsLookuper{})
 	}
 }
@@ -31,17 +31,8 @@ KEY	TYPE	DEFAULT	COMMENT
 // toTypeDescription converts Go types into a human readable description
 func toTypeDescription(t reflect.Type) string {
 	switch t.Kind() {
-	case reflect.Array, reflect.Slice:
-		if t.Elem().Kind() == reflect.Uint8 {
-			return "String"
-		}
-		return fmt.Sprintf("Comma-separated list of %s", toTypeDescription(t.Elem()))
-	case reflect.Map:
-		return fmt.Sprintf(
-			"Comma-separated list of %s:%s pairs",
-			toTypeDescription(t.Key()),
-			toTypeDescription(t.Elem()),
-		)
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return defaultValueFormat.toTypeDescription(t)
 	case reflect.Ptr:
 		return toTypeDescription(t.Elem())
 	case reflect.Struct:
@@ -85,23 +76,35 @@ func toTypeDescription(t reflect.Type) string {
 
 // PrintUsage writes usage information to stdout using the default header and table format
 func PrintUsage(spec interface{}) error {
+	return PrintUsageWithOptions(spec, Options{})
+}
+
+// PrintUsageWithOptions writes usage information to stdout using the default header and table format.
+// Keys of variables are qualified with the prefix from the specified options.
+func PrintUsageWithOptions(spec interface{}, opts Options) error {
 	// The default is to output the usage information as a table
 	// Create tabwriter instance to support table output
 	tabs := tabwriter.NewWriter(output, 1, 0, 4, ' ', 0)
 	defer tabs.Flush()
 
-	return Usagef(spec, tabs, DefaultTableFormat)
+	return UsagefWithOptions(spec, tabs, DefaultTableFormat, opts)
 }
 
 // Usagef writes usage information to the specified io.Writer using the specifed template specification
 func Usagef(spec interface{}, out io.Writer, format string) error {
+	return UsagefWithOptions(spec, out, format, Options{})
+}
+
+// UsagefWithOptions writes usage information to the specified io.Writer using the specifed template
+// specification. Keys of variables are qualified with the prefix from the specified options.
+func UsagefWithOptions(spec interface{}, out io.Writer, format string, opts Options) error {
 
 	// Specify the default usage template functions
 	functions := template.FuncMap{
 		"usage_key":         func(v envVarInfo) string { return v.Key },
-		"usage_description": func(v envVarInfo) string { return v.Comment },
-		"usage_type":        func(v envVarInfo) string { return toTypeDescription(v.Field.Type()) },
-		"usage_default":     func(v envVarInfo) string { return v.Default },
+		"usage_description": func(v envVarInfo) string { return v.Description() },
+		"usage_type":        func(v envVarInfo) string { return v.ValueFormat(opts).toTypeDescription(v.Field.Type()) },
+		"usage_default":     func(v envVarInfo) string { return v.MaskedDefault() },
 	}
 
 	tmpl, err := template.New("envconfig").Funcs(functions).Parse(format)
@@ -109,7 +112,7 @@ func Usagef(spec interface{}, out io.Writer, format string) error {
 		return err
 	}
 
-	infos, err := gatherInfo(spec)
+	infos, err := gatherInfo(spec, opts.Prefix, nil)
 	if err != nil {
 		return err
 	}
@@ -87,6 +87,21 @@ func TestUsageList(t *testing.T) {
 	require.Equal(t, testUsageListResult, buf.String())
 }
 
+func TestUsageWithPrefix(t *testing.T) {
+	var s struct {
+		Debug bool `env:"DEBUG" default:"true" comment:"foo"`
+		Port  int  `env:"PORT"`
+	}
+
+	buf := new(bytes.Buffer)
+	tabs := tabwriter.NewWriter(buf, 1, 0, 4, ' ', 0)
+	err := UsagefWithOptions(&s, tabs, DefaultTableFormat, Options{Prefix: "ENV_CONFIG_"})
+	tabs.Flush()
+
+	require.NoError(t, err)
+	require.Equal(t, testUsageTableResult, buf.String())
+}
+
 func TestUsageCustomFormat(t *testing.T) {
 
 	type Embedded struct {
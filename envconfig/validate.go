@@ -0,0 +1,293 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// validationRules are tags that validate the value of the struct field after unmarshaling.
+//
+// min, max and len limit the length of strings, slices and maps
+// and the value of numbers. oneof lists allowed values separated by comma.
+// pattern is a regular expression the value of the variable must match.
+var validationRules = []string{"min", "max", "len", "oneof", "pattern"}
+
+// Validate checks the unmarshaled value of the struct field against validation tags.
+// The value is the value of the variable the field was unmarshaled from.
+func (info *envVarInfo) Validate(value string) error {
+	for _, rule := range validationRules {
+		param, ok := info.Tag.Lookup(rule)
+		if !ok {
+			continue
+		}
+		msg, err := validateRule(rule, param, info.Field, value)
+		if err != nil {
+			return fmt.Errorf("invalid %s tag %q on struct field %s: %w", rule, param, info.Name, err)
+		}
+		if msg != "" {
+			return &ValidationError{Key: info.Key, Rule: rule, Param: param, Msg: msg}
+		}
+	}
+	return nil
+}
+
+// checkValidationTags makes sure that parameters of validation tags are valid
+// for the field of the specified type, so mistakes are found before any value is set.
+func checkValidationTags(tag reflect.StructTag, typ reflect.Type) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	for _, rule := range validationRules {
+		param, ok := tag.Lookup(rule)
+		if !ok {
+			continue
+		}
+		if err := checkRule(rule, param, typ); err != nil {
+			return fmt.Errorf("invalid %s tag %q: %v", rule, param, err)
+		}
+	}
+	return nil
+}
+
+func checkRule(rule, param string, typ reflect.Type) error {
+	switch rule {
+	case "pattern":
+		_, err := regexp.Compile(param)
+		return err
+	case "oneof":
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 {
+			typ = typ.Elem()
+		}
+		for _, option := range strings.Split(param, ",") {
+			if _, err := parseParam(strings.TrimSpace(option), typ); err != nil {
+				return err
+			}
+		}
+	case "min", "max", "len":
+		switch typ.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			_, err := strconv.Atoi(param)
+			return err
+		}
+		if _, ok := compareValues(reflect.Zero(typ), reflect.Zero(typ)); rule == "len" || !ok {
+			return fmt.Errorf("not supported for type %s", typ)
+		}
+		_, err := parseParam(param, typ)
+		return err
+	}
+	return nil
+}
+
+// validateRule returns a description of the violated rule or empty string if the field is valid.
+func validateRule(rule, param string, field reflect.Value, value string) (string, error) {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return "", nil
+		}
+		field = field.Elem()
+	}
+
+	switch rule {
+	case "pattern":
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return "", err
+		}
+		if !re.MatchString(value) {
+			return fmt.Sprintf("must match pattern %s", param), nil
+		}
+	case "oneof":
+		options := strings.Split(param, ",")
+		for i := range options {
+			options[i] = strings.TrimSpace(options[i])
+		}
+		values := []reflect.Value{field}
+		if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+			values = values[:0]
+			for i := 0; i < field.Len(); i++ {
+				values = append(values, field.Index(i))
+			}
+		}
+		for _, v := range values {
+			found, err := oneOf(v, options)
+			if err != nil {
+				return "", err
+			}
+			if !found {
+				return fmt.Sprintf("must be one of %s", strings.Join(options, ", ")), nil
+			}
+		}
+	case "min", "max", "len":
+		if length, ok := fieldLength(field); ok {
+			limit, err := strconv.Atoi(param)
+			if err != nil {
+				return "", err
+			}
+			if msg := compareLimit(rule, length-limit, param); msg != "" {
+				return "length " + msg, nil
+			}
+			return "", nil
+		}
+		if rule == "len" {
+			return "", fmt.Errorf("not supported for type %s", field.Type())
+		}
+		limit, err := parseParam(param, field.Type())
+		if err != nil {
+			return "", err
+		}
+		cmp, ok := compareValues(field, limit)
+		if !ok {
+			return "", fmt.Errorf("not supported for type %s", field.Type())
+		}
+		return compareLimit(rule, cmp, param), nil
+	}
+	return "", nil
+}
+
+// compareLimit returns a description of the violated rule given the sign of the difference
+// between the value and the limit or empty string if the rule is satisfied.
+func compareLimit(rule string, cmp int, param string) string {
+	switch {
+	case rule == "min" && cmp < 0:
+		return fmt.Sprintf("must be at least %s", param)
+	case rule == "max" && cmp > 0:
+		return fmt.Sprintf("must be at most %s", param)
+	case rule == "len" && cmp != 0:
+		return fmt.Sprintf("must be %s", param)
+	}
+	return ""
+}
+
+// fieldLength returns the length of strings, slices and maps.
+func fieldLength(field reflect.Value) (int, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String()), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return field.Len(), true
+	}
+	return 0, false
+}
+
+// parseParam parses the parameter of the tag as a value of the specified type.
+func parseParam(param string, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typ).Elem()
+	if err := unmarshalFieldValue(param, v, defaultValueFormat); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// compareValues compares two numbers of the same type.
+func compareValues(a, b reflect.Value) (int, bool) {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float() < b.Float(), a.Float() > b.Float()), true
+	}
+	return 0, false
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// oneOf reports whether the value is equal to any of the options parsed as values of the same type.
+func oneOf(v reflect.Value, options []string) (bool, error) {
+	for _, option := range options {
+		o, err := parseParam(option, v.Type())
+		if err != nil {
+			return false, err
+		}
+		if reflect.DeepEqual(o.Interface(), v.Interface()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validatorInfo is a struct of the specification that implements Validator.
+type validatorInfo struct {
+	Name      string
+	Type      reflect.Type
+	Validator Validator
+}
+
+// gatherValidators finds the specification, nested structs, elements of indexed slices
+// and values of maps populated from a prefix that implement Validator.
+// Nested structs go before the structs they are nested in. Validate of an embedded struct
+// is skipped when the outer struct implements Validator, because it is either promoted
+// to the outer struct or shadowed by it.
+func gatherValidators(s reflect.Value, name string) []validatorInfo {
+	self, isValidator := s.Addr().Interface().(Validator)
+
+	var validators []validatorInfo
+	for i := 0; i < s.NumField(); i++ {
+		f := followPointerChain(s.Field(i))
+		ftype := s.Type().Field(i)
+		if f.Kind() == reflect.Slice && isIndexed(ftype) && isStructElem(f.Type()) {
+			for j := 0; j < f.Len(); j++ {
+				name := fmt.Sprintf("%s[%d]", ftype.Name, j)
+				validators = append(validators, gatherValidators(followPointerChain(f.Index(j)), name)...)
+			}
+			continue
+		}
+		if _, ok := ftype.Tag.Lookup("envMapPrefix"); ok && f.Kind() == reflect.Map && isStructElem(f.Type()) {
+			keys := f.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, key := range keys {
+				// values of the map are not addressable, so a copy is validated
+				elem := reflect.New(f.Type().Elem()).Elem()
+				elem.Set(f.MapIndex(key))
+				name := fmt.Sprintf("%s[%s]", ftype.Name, key.String())
+				validators = append(validators, gatherValidators(followPointerChain(elem), name)...)
+			}
+			continue
+		}
+		if f.Kind() != reflect.Struct || implementsInterface(ftype.Type) {
+			continue
+		}
+		nested := gatherValidators(f, ftype.Name)
+		if n := len(nested); ftype.Anonymous && isValidator && n > 0 && nested[n-1].Type == f.Type() {
+			nested = nested[:n-1]
+		}
+		validators = append(validators, nested...)
+	}
+
+	if isValidator {
+		validators = append(validators, validatorInfo{Name: name, Type: s.Type(), Validator: self})
+	}
+	return validators
+}
+
+// validatorResults calls Validate on the specification and nested structs.
+// Each failure is reported as a result named after the struct.
+func validatorResults(spec interface{}) FieldUnmarshalResults {
+	s := reflect.ValueOf(spec).Elem()
+
+	var results FieldUnmarshalResults
+	for _, v := range gatherValidators(s, s.Type().String()) {
+		if err := v.Validator.Validate(); err != nil {
+			results = append(results, FieldUnmarshalResult{
+				FieldName: v.Name,
+				TypeName:  v.Type.String(),
+				Err:       fmt.Errorf("validate %s: %w", v.Name, err),
+			})
+		}
+	}
+	return results
+}
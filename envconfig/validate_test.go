@@ -0,0 +1,235 @@
+package envconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationTags(t *testing.T) {
+	t.Parallel()
+
+	type spec struct {
+		Port     int               `env:"PORT" min:"1" max:"65535"`
+		Level    string            `env:"LEVEL" oneof:"debug, info, warn, error"`
+		Name     string            `env:"NAME" pattern:"^[a-z]+$"`
+		Code     string            `env:"CODE" len:"3"`
+		Hosts    []string          `env:"HOSTS" min:"1" max:"2"`
+		Labels   map[string]string `env:"LABELS" max:"1"`
+		Rate     *float64          `env:"RATE" min:"0" max:"1" optional:"true"`
+		Timeout  time.Duration     `env:"TIMEOUT" min:"1s" max:"1m"`
+		Retries  uint              `env:"RETRIES" oneof:"1,3,5"`
+		Features []string          `env:"FEATURES" oneof:"a,b"`
+	}
+
+	valid := MapLookuper{
+		"PORT":     "8080",
+		"LEVEL":    "info",
+		"NAME":     "myapp",
+		"CODE":     "€ab",
+		"HOSTS":    "a,b",
+		"LABELS":   "a:b",
+		"RATE":     "0.5",
+		"TIMEOUT":  "30s",
+		"RETRIES":  "3",
+		"FEATURES": "a,b,a",
+	}
+
+	var s spec
+	_, err := UnmarshalFrom(&s, valid)
+	require.NoError(t, err)
+
+	tests := []struct {
+		key, value, msg string
+	}{
+		{key: "PORT", value: "0", msg: "must be at least 1"},
+		{key: "PORT", value: "65536", msg: "must be at most 65535"},
+		{key: "LEVEL", value: "trace", msg: "must be one of debug, info, warn, error"},
+		{key: "NAME", value: "MyApp", msg: "must match pattern ^[a-z]+$"},
+		{key: "CODE", value: "abcd", msg: "length must be 3"},
+		{key: "HOSTS", value: "", msg: "length must be at least 1"},
+		{key: "HOSTS", value: "a,b,c", msg: "length must be at most 2"},
+		{key: "LABELS", value: "a:b,c:d", msg: "length must be at most 1"},
+		{key: "RATE", value: "1.5", msg: "must be at most 1"},
+		{key: "TIMEOUT", value: "500ms", msg: "must be at least 1s"},
+		{key: "RETRIES", value: "2", msg: "must be one of 1, 3, 5"},
+		{key: "FEATURES", value: "a,c", msg: "must be one of a, b"},
+	}
+
+	for _, test := range tests {
+		l := MapLookuper{}
+		for k, v := range valid {
+			l[k] = v
+		}
+		l[test.key] = test.value
+
+		var s spec
+		_, err := UnmarshalFrom(&s, l)
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), test)
+		assert.Equal(t, test.key, validationErr.Key)
+		assert.Equal(t, test.msg, validationErr.Msg)
+	}
+}
+
+func TestValidationTagsInvalidSpecification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec interface{}
+		msg  string
+	}{
+		{
+			spec: &struct {
+				Port int `env:"PORT" min:"one" optional:"true"`
+			}{},
+			msg: `struct field Port: invalid min tag "one": strconv.ParseInt: parsing "one": invalid syntax`,
+		},
+		{
+			spec: &struct {
+				Name string `env:"NAME" pattern:"(" optional:"true"`
+			}{},
+			msg: "struct field Name: invalid pattern tag \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			spec: &struct {
+				Count int `env:"COUNT" len:"1"`
+			}{},
+			msg: `struct field Count: invalid len tag "1": not supported for type int`,
+		},
+		{
+			spec: &struct {
+				Datetime *time.Time `env:"DATETIME" max:"2020-01-01T00:00:00Z"`
+			}{},
+			msg: `struct field Datetime: invalid max tag "2020-01-01T00:00:00Z": not supported for type time.Time`,
+		},
+		{
+			spec: &struct {
+				Retries []uint `env:"RETRIES" oneof:"1,x"`
+			}{},
+			msg: `struct field Retries: invalid oneof tag "1,x": strconv.ParseUint: parsing "x": invalid syntax`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := UnmarshalFrom(test.spec, MapLookuper{})
+		assert.True(t, errors.Is(err, ErrInvalidSpecification), test.msg)
+		assert.EqualError(t, err, test.msg+": "+ErrInvalidSpecification.Error())
+	}
+}
+
+type tlsConfig struct {
+	Cert string `env:"CERT" optional:"true"`
+	Key  string `env:"KEY" optional:"true"`
+}
+
+func (c *tlsConfig) Validate() error {
+	if (c.Cert == "") != (c.Key == "") {
+		return errors.New("cert and key must be both set or both empty")
+	}
+	return nil
+}
+
+type Timeouts struct {
+	Read  time.Duration `env:"READ_TIMEOUT"`
+	Write time.Duration `env:"WRITE_TIMEOUT"`
+}
+
+func (t Timeouts) Validate() error {
+	if t.Write < t.Read {
+		return errors.New("write timeout must not be less than read timeout")
+	}
+	return nil
+}
+
+type serverConfig struct {
+	Timeouts
+	TLS  *tlsConfig `envPrefix:"TLS_"`
+	Port int        `env:"PORT"`
+}
+
+func (s *serverConfig) Validate() error {
+	if s.Port == 443 && s.TLS.Cert == "" {
+		return errors.New("TLS is required on port 443")
+	}
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	var s serverConfig
+	results, err := UnmarshalFrom(&s, MapLookuper{
+		"PORT":          "443",
+		"TLS_CERT":      "cert.pem",
+		"READ_TIMEOUT":  "1s",
+		"WRITE_TIMEOUT": "2s",
+	})
+	require.Error(t, err)
+	require.Len(t, results.Errors(), 1)
+
+	last := results[len(results)-1]
+	assert.Equal(t, "TLS", last.FieldName)
+	assert.Equal(t, "envconfig.tlsConfig", last.TypeName)
+	assert.Equal(t, "validate TLS: cert and key must be both set or both empty", err.Error())
+
+	s = serverConfig{}
+	_, err = UnmarshalFrom(&s, MapLookuper{
+		"PORT":          "443",
+		"READ_TIMEOUT":  "2s",
+		"WRITE_TIMEOUT": "1s",
+	})
+	var multiErr *MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Equal(t, []string{
+		"validate envconfig.serverConfig: TLS is required on port 443",
+	}, errorMessages(multiErr.Errors))
+
+	s = serverConfig{}
+	_, err = UnmarshalFrom(&s, MapLookuper{
+		"PORT":          "8080",
+		"READ_TIMEOUT":  "1s",
+		"WRITE_TIMEOUT": "1s",
+	})
+	require.NoError(t, err)
+}
+
+func TestValidatorSkippedOnFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	var s serverConfig
+	results, err := UnmarshalFrom(&s, MapLookuper{"PORT": "443"})
+	require.Error(t, err)
+	for _, result := range results {
+		assert.NotContains(t, result.FieldName, "serverConfig")
+	}
+}
+
+func errorMessages(errs []error) []string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return msgs
+}
+
+func TestValidatorPromotedFromEmbeddedStruct(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Timeouts
+	}
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"READ_TIMEOUT":  "2s",
+		"WRITE_TIMEOUT": "1s",
+	})
+
+	var multiErr *MultiError
+	require.True(t, errors.As(err, &multiErr))
+	require.Len(t, multiErr.Errors, 1)
+	assert.Contains(t, err.Error(), "write timeout must not be less than read timeout")
+}
@@ -0,0 +1,184 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Default separators of list elements and key-value pairs of maps.
+const (
+	DefaultListSeparator     = ","
+	DefaultKeyValueSeparator = ":"
+)
+
+// separatorNames are human readable names of separators used in usage.
+var separatorNames = map[string]string{
+	",":  "Comma",
+	";":  "Semicolon",
+	":":  "Colon",
+	"|":  "Pipe",
+	" ":  "Space",
+	"\t": "Tab",
+	"\n": "Newline",
+}
+
+// valueFormat describes how slices and maps are encoded in the value of the variable.
+// When Quoted is set, items enclosed in double quotes may contain separators
+// and "" stands for a literal quote. When Trim is set, whitespace around items is trimmed.
+type valueFormat struct {
+	Sep    string
+	KVSep  string
+	Quoted bool
+	Trim   bool
+}
+
+var defaultValueFormat = valueFormat{Sep: DefaultListSeparator, KVSep: DefaultKeyValueSeparator}
+
+// ValueFormat returns the format of the value of the variable. The `sep` and `kvsep` tags
+// take precedence over separators from the options. Quoting and trimming are enabled
+// with the `quoted` and `trim` tags.
+func (info *envVarInfo) ValueFormat(opts Options) valueFormat {
+	format := defaultValueFormat
+	if opts.ListSeparator != "" {
+		format.Sep = opts.ListSeparator
+	}
+	if opts.KeyValueSeparator != "" {
+		format.KVSep = opts.KeyValueSeparator
+	}
+	if sep := info.Tag.Get("sep"); sep != "" {
+		format.Sep = sep
+	}
+	if kvsep := info.Tag.Get("kvsep"); kvsep != "" {
+		format.KVSep = kvsep
+	}
+	format.Quoted, _ = strconv.ParseBool(info.Tag.Get("quoted"))
+	format.Trim, _ = strconv.ParseBool(info.Tag.Get("trim"))
+	return format
+}
+
+// item is an element of a list followed by one of the separators
+// or by nothing at the end of the value. Raw is the item as it appears in the value.
+type item struct {
+	Value string
+	Raw   string
+	Sep   string
+}
+
+// split splits the value into items separated by any of the separators.
+func (format valueFormat) split(value string, seps ...string) ([]item, error) {
+	var items []item
+	for {
+		var it item
+		var rest string
+		var err error
+		it.Value, rest, err = format.scanItem(value, seps)
+		if err != nil {
+			return nil, err
+		}
+		it.Raw = value[:len(value)-len(rest)]
+		it.Sep, value = cutSeparator(rest, seps)
+		items = append(items, it)
+		if it.Sep == "" {
+			return items, nil
+		}
+	}
+}
+
+// scanItem reads the item up to the next separator and returns it along with the rest of the value.
+func (format valueFormat) scanItem(s string, seps []string) (string, string, error) {
+	if format.Quoted {
+		t := s
+		if format.Trim {
+			t = strings.TrimLeftFunc(t, unicode.IsSpace)
+		}
+		if strings.HasPrefix(t, `"`) {
+			return format.scanQuoted(t[1:], seps)
+		}
+	}
+
+	i := 0
+	for i < len(s) && !hasSeparator(s[i:], seps) {
+		i++
+	}
+	value := s[:i]
+	if format.Trim {
+		value = strings.TrimSpace(value)
+	}
+	return value, s[i:], nil
+}
+
+// scanQuoted reads the quoted item. The opening quote is already consumed.
+func (format valueFormat) scanQuoted(s string, seps []string) (string, string, error) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '"')
+		if i < 0 {
+			return "", "", errors.New("unterminated quoted item")
+		}
+		b.WriteString(s[:i])
+		s = s[i+1:]
+		if !strings.HasPrefix(s, `"`) {
+			break
+		}
+		b.WriteByte('"')
+		s = s[1:]
+	}
+
+	if format.Trim {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	}
+	if s != "" && !hasSeparator(s, seps) {
+		return "", "", fmt.Errorf("unexpected text after quoted item %q", b.String())
+	}
+	return b.String(), s, nil
+}
+
+func hasSeparator(s string, seps []string) bool {
+	sep, _ := cutSeparator(s, seps)
+	return sep != ""
+}
+
+// cutSeparator removes the separator the string starts with and returns it along with the rest.
+func cutSeparator(s string, seps []string) (string, string) {
+	for _, sep := range seps {
+		if strings.HasPrefix(s, sep) {
+			return sep, s[len(sep):]
+		}
+	}
+	return "", s
+}
+
+// separatorName returns a human readable name of the separator.
+func separatorName(sep string) string {
+	if name, ok := separatorNames[sep]; ok {
+		return name
+	}
+	return fmt.Sprintf("%q", sep)
+}
+
+// toTypeDescription converts Go types into a human readable description
+// using separators of the specified format for slices and maps.
+func (format valueFormat) toTypeDescription(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Array, reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "String"
+		}
+		return fmt.Sprintf("%s-separated list of %s", separatorName(format.Sep), toTypeDescription(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf(
+			"%s-separated list of %s%s%s pairs",
+			separatorName(format.Sep),
+			toTypeDescription(t.Key()),
+			format.KVSep,
+			toTypeDescription(t.Elem()),
+		)
+	case reflect.Ptr:
+		return format.toTypeDescription(t.Elem())
+	}
+	return toTypeDescription(t)
+}
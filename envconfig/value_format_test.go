@@ -0,0 +1,167 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeparators(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Brokers map[string]int    `env:"BROKERS"`
+		Regexes []string          `env:"REGEXES" sep:";"`
+		URLs    map[string]string `env:"URLS" kvsep:"="`
+		Headers map[string]string `env:"HEADERS" sep:"|" kvsep:"="`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"BROKERS": "host:9092,host2:9092",
+		"REGEXES": "^a{1,3}$;^b+$",
+		"URLS":    "api=https://api.example.com,web=http://example.com:8080",
+		"HEADERS": "Accept=text/plain, text/html|X-Version=2",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"host": 9092, "host2": 9092}, s.Brokers)
+	assert.Equal(t, []string{"^a{1,3}$", "^b+$"}, s.Regexes)
+	assert.Equal(t, map[string]string{"api": "https://api.example.com", "web": "http://example.com:8080"}, s.URLs)
+	assert.Equal(t, map[string]string{"Accept": "text/plain, text/html", "X-Version": "2"}, s.Headers)
+}
+
+func TestSeparatorsValueWithKeyValueSeparator(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		URLs   map[string]string `env:"URLS" sep:";" kvsep:"="`
+		Tokens map[string]string `env:"TOKENS" kvsep:"="`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"URLS":   "api=https://x:1/?q=1&r=2;web=http://y",
+		"TOKENS": "a=b==,c=d",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"api": "https://x:1/?q=1&r=2", "web": "http://y"}, s.URLs)
+	assert.Equal(t, map[string]string{"a": "b==", "c": "d"}, s.Tokens)
+}
+
+func TestSeparatorsOptions(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Hosts  []string          `env:"HOSTS"`
+		Labels map[string]string `env:"LABELS"`
+		Ports  []int             `env:"PORTS" sep:","`
+	}
+
+	_, err := UnmarshalWithOptions(&s, Options{
+		Lookuper:          MapLookuper{"HOSTS": "a:1;b:2", "LABELS": "app=web;team=core", "PORTS": "80,443"},
+		ListSeparator:     ";",
+		KeyValueSeparator: "=",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a:1", "b:2"}, s.Hosts)
+	assert.Equal(t, map[string]string{"app": "web", "team": "core"}, s.Labels)
+	assert.Equal(t, []int{80, 443}, s.Ports)
+}
+
+func TestSeparatorsUsage(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Hosts   []string          `env:"HOSTS"`
+		Regexes []string          `env:"REGEXES" sep:";"`
+		Labels  map[string]string `env:"LABELS" sep:" " kvsep:"="`
+		Tags    []string          `env:"TAGS" sep:"++"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := UsagefWithOptions(&s, buf, "{{range .}}{{usage_key .}}|{{usage_type .}}\n{{end}}", Options{ListSeparator: "|"})
+	require.NoError(t, err)
+	assert.Equal(t, "HOSTS|Pipe-separated list of String\n"+
+		"LABELS|Space-separated list of String=String pairs\n"+
+		"REGEXES|Semicolon-separated list of String\n"+
+		"TAGS|\"++\"-separated list of String\n", buf.String())
+}
+
+func TestQuoted(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Regexes []string          `env:"REGEXES" quoted:"true"`
+		Names   []string          `env:"NAMES" quoted:"true" trim:"true"`
+		Labels  map[string]string `env:"LABELS" quoted:"true"`
+		Plain   []string          `env:"PLAIN"`
+		Trimmed []int             `env:"TRIMMED" trim:"true"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"REGEXES": `"^a{1,3}$",^b+$,"say ""hi"""`,
+		"NAMES":   ` "Doe, John" , Smith ,`,
+		"LABELS":  `"a:b":"c,d",e:f`,
+		"PLAIN":   `"a,b"`,
+		"TRIMMED": "1, 2 ,3",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"^a{1,3}$", "^b+$", `say "hi"`}, s.Regexes)
+	assert.Equal(t, []string{"Doe, John", "Smith", ""}, s.Names)
+	assert.Equal(t, map[string]string{"a:b": "c,d", "e": "f"}, s.Labels)
+	assert.Equal(t, []string{`"a`, `b"`}, s.Plain)
+	assert.Equal(t, []int{1, 2, 3}, s.Trimmed)
+}
+
+func TestQuotedValueWithKeyValueSeparator(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Labels  map[string]string `env:"LABELS" quoted:"true"`
+		Trimmed map[string]string `env:"TRIMMED" quoted:"true" trim:"true"`
+	}
+
+	_, err := UnmarshalFrom(&s, MapLookuper{
+		"LABELS":  `k:x:"y,z",a:"b:c":d`,
+		"TRIMMED": ` k : x : " y,z " `,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"k": "x:y,z", "a": "b:c:d"}, s.Labels)
+	assert.Equal(t, map[string]string{"k": "x: y,z "}, s.Trimmed)
+}
+
+func TestQuotedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{value: `"a,b`, err: "unterminated quoted item"},
+		{value: `"a"b,c`, err: `unexpected text after quoted item "a"`},
+	}
+
+	for _, test := range tests {
+		var s struct {
+			List []string `env:"LIST" quoted:"true"`
+		}
+		_, err := UnmarshalFrom(&s, MapLookuper{"LIST": test.value})
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr), test.value)
+		assert.EqualError(t, parseErr.Err, test.err, test.value)
+	}
+
+	var s struct {
+		Map map[string]string `env:"MAP"`
+	}
+	_, err := UnmarshalFrom(&s, MapLookuper{"MAP": "a:b,c,f:g"})
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.EqualError(t, parseErr.Err, `invalid map item: "c"`)
+}